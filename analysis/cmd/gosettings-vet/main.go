@@ -0,0 +1,16 @@
+// Command gosettings-vet runs the settingsfields analyzer, reporting
+// fields of settings structs forgotten in their Copy and OverrideWith
+// methods. It can be run standalone on packages, or with go vet:
+//
+//	go install github.com/qdm12/gosettings/analysis/cmd/gosettings-vet@latest
+//	go vet -vettool=$(which gosettings-vet) ./...
+package main
+
+import (
+	"github.com/qdm12/gosettings/analysis/settingsfields"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(settingsfields.Analyzer)
+}
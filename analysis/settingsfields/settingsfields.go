@@ -0,0 +1,294 @@
+// Package settingsfields defines an analyzer reporting fields of
+// settings structs forgotten in their Copy and OverrideWith methods.
+package settingsfields
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const doc = `check settings struct fields are all handled in Copy and OverrideWith
+
+For each struct type with a method Copy returning the struct type, or
+with a method OverrideWith taking the struct type as single argument,
+report exported fields never referenced in the method body. For Copy,
+also report pointer, slice and map fields not deep copied with a Copy*
+function of the github.com/qdm12/gosettings package.
+
+Methods passing their receiver (or OverrideWith argument) as a whole
+value argument to another function or method are not checked, since
+fields may be handled there. Calling a method on the receiver, such
+as s.String() in a log line, is not considered as such.`
+
+// Analyzer reports fields of settings structs forgotten in
+// their Copy and OverrideWith methods.
+var Analyzer = &analysis.Analyzer{ //nolint:gochecknoglobals
+	Name:     "settingsfields",
+	Doc:      doc,
+	Run:      run,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+}
+
+const gosettingsPath = "github.com/qdm12/gosettings"
+
+func run(pass *analysis.Pass) (result any, err error) {
+	nodeInspector := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector) //nolint:forcetypeassert
+	nodeFilter := []ast.Node{(*ast.FuncDecl)(nil)}
+	nodeInspector.Preorder(nodeFilter, func(node ast.Node) {
+		funcDecl := node.(*ast.FuncDecl) //nolint:forcetypeassert
+		if funcDecl.Recv == nil || funcDecl.Body == nil {
+			return
+		}
+
+		method, ok := pass.TypesInfo.Defs[funcDecl.Name].(*types.Func)
+		if !ok {
+			return
+		}
+		named, structType, ok := receiverStruct(method)
+		if !ok {
+			return
+		}
+
+		signature := method.Type().(*types.Signature) //nolint:forcetypeassert
+		switch funcDecl.Name.Name {
+		case "Copy":
+			if signature.Params().Len() != 0 || signature.Results().Len() != 1 ||
+				!isNamedType(signature.Results().At(0).Type(), named) {
+				return
+			}
+			tracked := []*types.Var{signature.Recv()}
+			checkCopy(pass, funcDecl, named, structType, tracked)
+		case "OverrideWith":
+			if signature.Params().Len() != 1 ||
+				!isNamedType(signature.Params().At(0).Type(), named) {
+				return
+			}
+			tracked := []*types.Var{signature.Recv(), signature.Params().At(0)}
+			checkOverrideWith(pass, funcDecl, named, structType, tracked)
+		}
+	})
+	return nil, nil //nolint:nilnil
+}
+
+// receiverStruct returns the named type and struct type of the receiver
+// of the method given, and false if the receiver is not a struct.
+func receiverStruct(method *types.Func) (named *types.Named,
+	structType *types.Struct, ok bool) {
+	signature := method.Type().(*types.Signature) //nolint:forcetypeassert
+	receiverType := signature.Recv().Type()
+	pointer, isPointer := receiverType.(*types.Pointer)
+	if isPointer {
+		receiverType = pointer.Elem()
+	}
+	named, ok = receiverType.(*types.Named)
+	if !ok {
+		return nil, nil, false
+	}
+	structType, ok = named.Underlying().(*types.Struct)
+	return named, structType, ok
+}
+
+// isNamedType returns true if the type given is the named type
+// given or a pointer to it.
+func isNamedType(t types.Type, named *types.Named) bool {
+	pointer, ok := t.(*types.Pointer)
+	if ok {
+		t = pointer.Elem()
+	}
+	return types.Identical(t, named)
+}
+
+func checkCopy(pass *analysis.Pass, funcDecl *ast.FuncDecl, named *types.Named,
+	structType *types.Struct, tracked []*types.Var) {
+	usage := analyzeBody(pass, funcDecl.Body, structType, tracked)
+	if usage.delegated {
+		return
+	}
+
+	for _, field := range exportedFields(structType) {
+		copied := usage.referenced[field] || usage.wholeCopied
+		if !copied {
+			reportf(pass, funcDecl, named, "field %s is not copied", field.Name())
+			continue
+		}
+
+		if usage.deepCopied[field] {
+			continue
+		}
+		switch field.Type().Underlying().(type) {
+		case *types.Pointer:
+			reportf(pass, funcDecl, named,
+				"pointer field %s is not deep copied with gosettings.CopyPointer", field.Name())
+		case *types.Slice:
+			reportf(pass, funcDecl, named,
+				"slice field %s is not deep copied with gosettings.CopySlice", field.Name())
+		case *types.Map:
+			reportf(pass, funcDecl, named,
+				"map field %s is not deep copied with gosettings.CopyMap", field.Name())
+		}
+	}
+}
+
+func checkOverrideWith(pass *analysis.Pass, funcDecl *ast.FuncDecl, named *types.Named,
+	structType *types.Struct, tracked []*types.Var) {
+	usage := analyzeBody(pass, funcDecl.Body, structType, tracked)
+	if usage.delegated || usage.wholeCopied {
+		return
+	}
+
+	for _, field := range exportedFields(structType) {
+		if !usage.referenced[field] {
+			reportf(pass, funcDecl, named, "field %s is not overridden", field.Name())
+		}
+	}
+}
+
+func reportf(pass *analysis.Pass, funcDecl *ast.FuncDecl, named *types.Named,
+	format string, args ...any) {
+	prefix := named.Obj().Name() + "." + funcDecl.Name.Name + ": "
+	pass.Reportf(funcDecl.Name.Pos(), prefix+format, args...)
+}
+
+func exportedFields(structType *types.Struct) (fields []*types.Var) {
+	fields = make([]*types.Var, 0, structType.NumFields())
+	for i := 0; i < structType.NumFields(); i++ {
+		field := structType.Field(i)
+		if field.Exported() {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+type bodyUsage struct {
+	// referenced contains fields referenced anywhere in the body.
+	referenced map[*types.Var]bool
+	// deepCopied contains fields referenced in arguments of a
+	// Copy* function of the gosettings package.
+	deepCopied map[*types.Var]bool
+	// wholeCopied is true if a tracked variable is used as a
+	// whole value, for example with `copied := *s`.
+	wholeCopied bool
+	// delegated is true if a tracked variable is passed as a
+	// whole value argument to a function or method call.
+	delegated bool
+}
+
+// analyzeBody analyzes how the fields of the struct type given are
+// used in the body, as well as how the tracked variables (receiver
+// and arguments) are used as a whole.
+func analyzeBody(pass *analysis.Pass, body *ast.BlockStmt,
+	structType *types.Struct, tracked []*types.Var) (usage bodyUsage) {
+	usage.referenced = make(map[*types.Var]bool)
+	usage.deepCopied = make(map[*types.Var]bool)
+
+	fields := make(map[*types.Var]bool, structType.NumFields())
+	for i := 0; i < structType.NumFields(); i++ {
+		fields[structType.Field(i)] = true
+	}
+	isTracked := func(expr ast.Expr) bool {
+		ident, ok := unwrap(expr).(*ast.Ident)
+		if !ok {
+			return false
+		}
+		object := pass.TypesInfo.Uses[ident]
+		for _, variable := range tracked {
+			if object == variable {
+				return true
+			}
+		}
+		return false
+	}
+
+	// selected contains identifiers of tracked variables used
+	// to select one of their fields or methods, which are not
+	// uses of the variable as a whole value.
+	selected := make(map[ast.Expr]bool)
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.Ident:
+			field, ok := pass.TypesInfo.Uses[node].(*types.Var)
+			if ok && fields[field] {
+				usage.referenced[field] = true
+			}
+		case *ast.SelectorExpr:
+			_, ok := pass.TypesInfo.Selections[node]
+			if ok && isTracked(node.X) {
+				selected[unwrap(node.X)] = true
+			}
+		case *ast.CallExpr:
+			analyzeCall(pass, node, fields, isTracked, &usage)
+		}
+		return true
+	})
+
+	if usage.delegated {
+		return usage
+	}
+
+	ast.Inspect(body, func(node ast.Node) bool {
+		ident, ok := node.(*ast.Ident)
+		if ok && isTracked(ident) && !selected[ident] {
+			usage.wholeCopied = true
+		}
+		return !usage.wholeCopied
+	})
+	return usage
+}
+
+func analyzeCall(pass *analysis.Pass, call *ast.CallExpr, fields map[*types.Var]bool,
+	isTracked func(expr ast.Expr) bool, usage *bodyUsage) {
+	callee := typeutil.Callee(pass.TypesInfo, call)
+	function, ok := callee.(*types.Func)
+	if ok && function.Pkg() != nil && function.Pkg().Path() == gosettingsPath &&
+		strings.HasPrefix(function.Name(), "Copy") {
+		for _, argument := range call.Args {
+			ast.Inspect(argument, func(node ast.Node) bool {
+				ident, ok := node.(*ast.Ident)
+				if !ok {
+					return true
+				}
+				field, ok := pass.TypesInfo.Uses[ident].(*types.Var)
+				if ok && fields[field] {
+					usage.deepCopied[field] = true
+				}
+				return true
+			})
+		}
+		return
+	}
+
+	for _, argument := range call.Args {
+		if isTracked(argument) {
+			usage.delegated = true
+			return
+		}
+	}
+}
+
+// unwrap removes parentheses, dereferences and address
+// operators around the expression given.
+func unwrap(expr ast.Expr) ast.Expr {
+	for {
+		switch e := expr.(type) {
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.UnaryExpr:
+			if e.Op != token.AND {
+				return expr
+			}
+			expr = e.X
+		default:
+			return expr
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package a
+
+import (
+	"log"
+
+	"github.com/qdm12/gosettings"
+)
+
+type Complete struct {
+	Name    string
+	Port    *uint16
+	Names   []string
+	Server  Server
+	private int
+}
+
+func (c *Complete) Copy() (copied Complete) {
+	return Complete{
+		Name:   c.Name,
+		Port:   gosettings.CopyPointer(c.Port),
+		Names:  gosettings.CopySlice(c.Names),
+		Server: c.Server.Copy(),
+	}
+}
+
+func (c *Complete) OverrideWith(other Complete) {
+	c.Name = gosettings.OverrideWithComparable(c.Name, other.Name)
+	c.Port = gosettings.OverrideWithPointer(c.Port, other.Port)
+	c.Names = other.Names
+	c.Server.OverrideWith(other.Server)
+}
+
+type Server struct {
+	Address string
+}
+
+func (s Server) Copy() Server {
+	return s
+}
+
+func (s *Server) OverrideWith(other Server) {
+	*s = other
+}
+
+type Missing struct {
+	Name  string
+	Port  *uint16
+	Names []string
+}
+
+func (m *Missing) Copy() (copied Missing) { // want `Missing.Copy: field Port is not copied` `Missing.Copy: slice field Names is not deep copied with gosettings.CopySlice`
+	return Missing{
+		Name:  m.Name,
+		Names: m.Names,
+	}
+}
+
+func (m *Missing) OverrideWith(other Missing) { // want `Missing.OverrideWith: field Names is not overridden`
+	m.Name = gosettings.OverrideWithComparable(m.Name, other.Name)
+	m.Port = gosettings.OverrideWithPointer(m.Port, other.Port)
+}
+
+type Shallow struct {
+	Name   string
+	Port   *uint16
+	Labels map[string]string
+}
+
+func (s Shallow) Copy() Shallow { // want `Shallow.Copy: pointer field Port is not deep copied with gosettings.CopyPointer` `Shallow.Copy: map field Labels is not deep copied with gosettings.CopyMap`
+	return s
+}
+
+type Maps struct {
+	Labels map[string]string
+}
+
+func (m *Maps) Copy() Maps {
+	return Maps{Labels: gosettings.CopyMap(m.Labels)}
+}
+
+type Delegated struct {
+	Name string
+	Port *uint16
+}
+
+func (d *Delegated) Copy() Delegated {
+	return copyDelegated(d)
+}
+
+func (d *Delegated) OverrideWith(other Delegated) {
+	d.override(other)
+}
+
+func (d *Delegated) override(other Delegated) {}
+
+func copyDelegated(d *Delegated) Delegated {
+	return Delegated{}
+}
+
+type Logged struct {
+	Name string
+	Port *uint16
+}
+
+func (l *Logged) Copy() Logged { // want `Logged.Copy: field Port is not copied`
+	log.Println("copying", l.String())
+	return Logged{Name: l.Name}
+}
+
+func (l *Logged) OverrideWith(other Logged) { // want `Logged.OverrideWith: field Port is not overridden`
+	log.Println("overriding with", other.String())
+	l.Name = gosettings.OverrideWithComparable(l.Name, other.Name)
+}
+
+func (l Logged) String() string {
+	return l.Name
+}
+
+type NotSettings struct {
+	Name string
+}
+
+func (n *NotSettings) Copy(deep bool) NotSettings {
+	return NotSettings{}
+}
+
+func (n *NotSettings) OverrideWith(name string) {}
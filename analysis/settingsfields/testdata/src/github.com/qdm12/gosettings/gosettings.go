@@ -0,0 +1,45 @@
+package gosettings
+
+func CopyPointer[T any](original *T) (copied *T) {
+	if original == nil {
+		return nil
+	}
+	copied = new(T)
+	*copied = *original
+	return copied
+}
+
+func CopySlice[T any](original []T) (copied []T) {
+	if original == nil {
+		return nil
+	}
+	copied = make([]T, len(original))
+	copy(copied, original)
+	return copied
+}
+
+func CopyMap[K comparable, V any](original map[K]V) (copied map[K]V) {
+	if original == nil {
+		return nil
+	}
+	copied = make(map[K]V, len(original))
+	for key, value := range original {
+		copied[key] = value
+	}
+	return copied
+}
+
+func OverrideWithPointer[T any](existing, other *T) (result *T) {
+	if other == nil {
+		return existing
+	}
+	return CopyPointer(other)
+}
+
+func OverrideWithComparable[T comparable](existing, other T) (result T) {
+	var zero T
+	if other == zero {
+		return existing
+	}
+	return other
+}
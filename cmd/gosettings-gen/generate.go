@@ -0,0 +1,364 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// generateFromDirectory parses the Go package in the directory given
+// and returns the formatted generated code for the struct types given.
+func generateFromDirectory(directory string, typeNames []string,
+	outputFileName string) (code []byte, err error) {
+	pkg, err := parseDirectory(directory, outputFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	nameToStruct := structTypes(pkg.files)
+	stringTypeNames := stringTypes(pkg.files)
+	structs := make([]structInfo, len(typeNames))
+	for i, typeName := range typeNames {
+		structs[i], err = parseStruct(strings.TrimSpace(typeName),
+			nameToStruct, stringTypeNames)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return generate(pkg.name, pkg.imports, structs)
+}
+
+func generate(packageName string, packageImports map[string]string,
+	structs []structInfo) (code []byte, err error) {
+	body := new(bytes.Buffer)
+	for _, info := range structs {
+		writeSetDefaults(body, info)
+		writeCopy(body, info)
+		writeOverrideWith(body, info)
+		writeValidate(body, info)
+		writeString(body, info)
+	}
+
+	buffer := new(bytes.Buffer)
+	buffer.WriteString("// Code generated by gosettings-gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(buffer, "package %s\n\n", packageName)
+	writeImports(buffer, body.String(), packageImports)
+	buffer.Write(body.Bytes())
+
+	code, err = format.Source(buffer.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return code, nil
+}
+
+// writeImports writes the imports used in the generated body, which
+// can be standard library packages, gosettings packages, or packages
+// imported by the package files and used in default values or types.
+func writeImports(buffer *bytes.Buffer, body string, packageImports map[string]string) {
+	nameToPath := map[string]string{
+		"fmt":        "fmt",
+		"strings":    "strings",
+		"gosettings": "github.com/qdm12/gosettings",
+		"validate":   "github.com/qdm12/gosettings/validate",
+	}
+	for name, importPath := range packageImports {
+		nameToPath[name] = importPath
+	}
+
+	var standardImports, otherImports []string
+	for name, importPath := range nameToPath {
+		usageRegex := regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\.`)
+		if !usageRegex.MatchString(body) {
+			continue
+		}
+		importLine := strconv.Quote(importPath)
+		if name != path.Base(importPath) {
+			importLine = name + " " + importLine
+		}
+		if strings.Contains(importPath, ".") {
+			otherImports = append(otherImports, importLine)
+		} else {
+			standardImports = append(standardImports, importLine)
+		}
+	}
+	sort.Strings(standardImports)
+	sort.Strings(otherImports)
+
+	if len(standardImports)+len(otherImports) == 0 {
+		return
+	}
+	buffer.WriteString("import (\n")
+	for _, importLine := range standardImports {
+		buffer.WriteString(importLine + "\n")
+	}
+	if len(standardImports) > 0 && len(otherImports) > 0 {
+		buffer.WriteString("\n")
+	}
+	for _, importLine := range otherImports {
+		buffer.WriteString(importLine + "\n")
+	}
+	buffer.WriteString(")\n\n")
+}
+
+func writeSetDefaults(buffer *bytes.Buffer, info structInfo) {
+	fmt.Fprintf(buffer, "// SetDefaults sets the default values of all unset fields.\n")
+	fmt.Fprintf(buffer, "func (s *%s) SetDefaults() {\n", info.name)
+	for _, field := range info.fields {
+		switch {
+		case field.kind == kindNested:
+			fmt.Fprintf(buffer, "s.%s.SetDefaults()\n", field.name)
+		case field.kind == kindNestedPointer:
+			fmt.Fprintf(buffer, "s.%[1]s = gosettings.DefaultNested(s.%[1]s)\n", field.name)
+		case field.defaultValue == "":
+		case field.kind == kindPointer:
+			fmt.Fprintf(buffer, "s.%[1]s = gosettings.DefaultPointer(s.%[1]s, %[2]s)\n",
+				field.name, field.defaultValue)
+		case field.kind == kindSlice:
+			fmt.Fprintf(buffer, "s.%[1]s = gosettings.DefaultSlice(s.%[1]s, %[2]s)\n",
+				field.name, field.defaultValue)
+		case field.kind == kindMap:
+			fmt.Fprintf(buffer, "s.%[1]s = gosettings.DefaultMap(s.%[1]s, %[2]s)\n",
+				field.name, field.defaultValue)
+		default:
+			fmt.Fprintf(buffer, "s.%[1]s = gosettings.DefaultComparable(s.%[1]s, %[2]s)\n",
+				field.name, field.defaultValue)
+		}
+
+		if field.nestedElements {
+			fmt.Fprintf(buffer, "for i := range s.%s {\n", field.name)
+			fmt.Fprintf(buffer, "s.%s[i].SetDefaults()\n", field.name)
+			fmt.Fprintf(buffer, "}\n")
+		}
+	}
+	buffer.WriteString("}\n\n")
+}
+
+// writeCopy writes the Copy method, which starts from a shallow
+// copy of the settings, to keep unexported fields, and then deep
+// copies the exported pointer, slice, map and nested fields.
+// Slice elements and map values which are pointers or nested
+// settings are deep copied as well, but slice elements and map
+// values which are slices or maps are shallow copied.
+func writeCopy(buffer *bytes.Buffer, info structInfo) {
+	fmt.Fprintf(buffer, "// Copy returns a deep copy of the settings.\n")
+	fmt.Fprintf(buffer, "func (s *%[1]s) Copy() (copied %[1]s) {\n", info.name)
+	fmt.Fprintf(buffer, "copied = *s\n")
+	for _, field := range info.fields {
+		switch {
+		case field.kind == kindNested:
+			fmt.Fprintf(buffer, "copied.%[1]s = s.%[1]s.Copy()\n", field.name)
+		case field.kind == kindNestedPointer:
+			fmt.Fprintf(buffer, "copied.%[1]s = gosettings.CopyNested(s.%[1]s)\n", field.name)
+		case field.kind == kindPointer:
+			fmt.Fprintf(buffer, "copied.%[1]s = gosettings.CopyPointer(s.%[1]s)\n", field.name)
+		case field.nestedElements:
+			fmt.Fprintf(buffer, "copied.%[1]s = gosettings.CopySliceDeep(s.%[1]s)\n", field.name)
+		case field.kind == kindSlice && field.elementKind == kindComparable:
+			fmt.Fprintf(buffer, "copied.%[1]s = gosettings.CopySlice(s.%[1]s)\n", field.name)
+		case field.kind == kindMap && field.elementKind == kindComparable:
+			fmt.Fprintf(buffer, "copied.%[1]s = gosettings.CopyMap(s.%[1]s)\n", field.name)
+		case field.kind == kindSlice, field.kind == kindMap:
+			writeCopyElements(buffer, field)
+		}
+	}
+	buffer.WriteString("return copied\n}\n\n")
+}
+
+// writeCopyElements writes the code deep copying each slice element
+// or map value of the field given, which are pointers or nested settings.
+func writeCopyElements(buffer *bytes.Buffer, field fieldInfo) {
+	var elementCopy string
+	switch field.elementKind { //nolint:exhaustive
+	case kindPointer:
+		elementCopy = "gosettings.CopyPointer(value)"
+	case kindNested:
+		elementCopy = "value.Copy()"
+	case kindNestedPointer:
+		elementCopy = "gosettings.CopyNested(value)"
+	}
+
+	index := "i"
+	if field.kind == kindMap {
+		index = "key"
+	}
+	fmt.Fprintf(buffer, "if s.%s != nil {\n", field.name)
+	fmt.Fprintf(buffer, "copied.%s = make(%s, len(s.%s))\n",
+		field.name, field.typeName, field.name)
+	fmt.Fprintf(buffer, "for %s, value := range s.%s {\n", index, field.name)
+	fmt.Fprintf(buffer, "copied.%s[%s] = %s\n", field.name, index, elementCopy)
+	fmt.Fprintf(buffer, "}\n}\n")
+}
+
+func writeOverrideWith(buffer *bytes.Buffer, info structInfo) {
+	fmt.Fprintf(buffer, "// OverrideWith overrides the fields of the settings\n")
+	fmt.Fprintf(buffer, "// with the set fields of the other settings.\n")
+	fmt.Fprintf(buffer, "func (s *%s) OverrideWith(other %s) {\n", info.name, info.name)
+	for _, field := range info.fields {
+		switch field.kind {
+		case kindNested:
+			fmt.Fprintf(buffer, "s.%[1]s.OverrideWith(other.%[1]s)\n", field.name)
+		case kindNestedPointer:
+			fmt.Fprintf(buffer, "s.%[1]s = gosettings.OverrideWithNested(s.%[1]s, other.%[1]s)\n",
+				field.name)
+		case kindPointer:
+			fmt.Fprintf(buffer, "s.%[1]s = gosettings.OverrideWithPointer(s.%[1]s, other.%[1]s)\n",
+				field.name)
+		case kindSlice:
+			fmt.Fprintf(buffer, "s.%[1]s = gosettings.OverrideWithSlice(s.%[1]s, other.%[1]s)\n",
+				field.name)
+		case kindMap:
+			fmt.Fprintf(buffer, "s.%[1]s = gosettings.OverrideWithMap(s.%[1]s, other.%[1]s)\n",
+				field.name)
+		default:
+			fmt.Fprintf(buffer, "s.%[1]s = gosettings.OverrideWithComparable(s.%[1]s, other.%[1]s)\n",
+				field.name)
+		}
+	}
+	buffer.WriteString("}\n\n")
+}
+
+func writeValidate(buffer *bytes.Buffer, info structInfo) {
+	fmt.Fprintf(buffer, "// Validate validates the settings and returns an error\n")
+	fmt.Fprintf(buffer, "// if one setting is not valid. It should be called after\n")
+	fmt.Fprintf(buffer, "// SetDefaults, and nil pointer fields are not validated.\n")
+	fmt.Fprintf(buffer, "func (s *%s) Validate() (err error) {\n", info.name)
+	for _, field := range info.fields {
+		value := "s." + field.name
+		if field.kind == kindPointer {
+			value = "*" + value
+		}
+
+		if field.nestedElements {
+			fmt.Fprintf(buffer, "for i := range s.%s {\n", field.name)
+			fmt.Fprintf(buffer, "err = s.%s[i].Validate()\n", field.name)
+			fmt.Fprintf(buffer, "if err != nil {\n")
+			fmt.Fprintf(buffer, "return fmt.Errorf(\"%s %%d: %%w\", i, err)\n", lowerFirst(field.name))
+			fmt.Fprintf(buffer, "}\n}\n\n")
+			continue
+		}
+
+		var call string
+		switch {
+		case field.kind == kindNested, field.kind == kindNestedPointer:
+			call = fmt.Sprintf("s.%s.Validate()", field.name)
+		case len(field.oneOf) > 0 && field.kind == kindSlice:
+			call = fmt.Sprintf("validate.AreAllOneOf(%s, []%s{%s})",
+				value, field.elementType, strings.Join(field.oneOf, ", "))
+		case len(field.oneOf) > 0:
+			call = fmt.Sprintf("validate.IsOneOf(%s, %s)",
+				value, strings.Join(field.oneOf, ", "))
+		case field.rangeMin != "":
+			call = fmt.Sprintf("validate.NumberBetween(%s, %s, %s)",
+				value, field.rangeMin, field.rangeMax)
+		default:
+			continue
+		}
+
+		isPointer := field.kind == kindPointer || field.kind == kindNestedPointer
+		if isPointer {
+			fmt.Fprintf(buffer, "if s.%s != nil {\n", field.name)
+		}
+		fmt.Fprintf(buffer, "err = %s\n", call)
+		fmt.Fprintf(buffer, "if err != nil {\n")
+		fmt.Fprintf(buffer, "return fmt.Errorf(\"%s: %%w\", err)\n", lowerFirst(field.name))
+		fmt.Fprintf(buffer, "}\n")
+		if isPointer {
+			fmt.Fprintf(buffer, "}\n")
+		}
+		fmt.Fprintf(buffer, "\n")
+	}
+	buffer.WriteString("return nil\n}\n\n")
+}
+
+func writeString(buffer *bytes.Buffer, info structInfo) {
+	fmt.Fprintf(buffer, "// String returns a string representation of the settings.\n")
+	fmt.Fprintf(buffer, "func (s %s) String() string {\n", info.name)
+	fmt.Fprintf(buffer, "builder := new(strings.Builder)\n")
+	fmt.Fprintf(buffer, "builder.WriteString(%q)\n", info.name+":")
+	for _, field := range info.fields {
+		switch field.kind {
+		case kindNested:
+			fmt.Fprintf(buffer, "builder.WriteString(%q)\n", "\n- "+field.name+":")
+			fmt.Fprintf(buffer, "builder.WriteString(%s)\n",
+				nestedStringExpression(field, "s."+field.name, "  "))
+			continue
+		case kindNestedPointer:
+			fmt.Fprintf(buffer, "builder.WriteString(%q)\n", "\n- "+field.name+":")
+			fmt.Fprintf(buffer, "if s.%s == nil {\n", field.name)
+			fmt.Fprintf(buffer, "builder.WriteString(\" [not set]\")\n")
+			fmt.Fprintf(buffer, "} else {\n")
+			fmt.Fprintf(buffer, "builder.WriteString(%s)\n",
+				nestedStringExpression(field, "s."+field.name, "  "))
+			fmt.Fprintf(buffer, "}\n")
+			continue
+		}
+
+		if field.nestedElements {
+			fmt.Fprintf(buffer, "builder.WriteString(%q)\n", "\n- "+field.name+":")
+			fmt.Fprintf(buffer, "if len(s.%s) == 0 {\n", field.name)
+			fmt.Fprintf(buffer, "builder.WriteString(\" [not set]\")\n")
+			fmt.Fprintf(buffer, "}\n")
+			fmt.Fprintf(buffer, "for i := range s.%s {\n", field.name)
+			fmt.Fprintf(buffer, "builder.WriteString(fmt.Sprintf(\"\\n  - %%d:\", i+1))\n")
+			fmt.Fprintf(buffer, "builder.WriteString(%s)\n",
+				nestedStringExpression(field, "s."+field.name+"[i]", "    "))
+			fmt.Fprintf(buffer, "}\n")
+			continue
+		}
+
+		fmt.Fprintf(buffer, "builder.WriteString(%q)\n", "\n- "+field.name+": ")
+		if field.kind == kindPointer {
+			fmt.Fprintf(buffer, "if s.%s == nil {\n", field.name)
+			fmt.Fprintf(buffer, "builder.WriteString(\"[not set]\")\n")
+			fmt.Fprintf(buffer, "} else {\n")
+			fmt.Fprintf(buffer, "builder.WriteString(%s)\n", stringExpression(field, "*s."+field.name))
+			fmt.Fprintf(buffer, "}\n")
+			continue
+		}
+		fmt.Fprintf(buffer, "builder.WriteString(%s)\n", stringExpression(field, "s."+field.name))
+	}
+	buffer.WriteString("return builder.String()\n}\n\n")
+}
+
+// stringExpression returns the Go expression converting the field
+// value expression given to a string.
+func stringExpression(field fieldInfo, value string) (expression string) {
+	isString := (field.kind == kindComparable || field.kind == kindPointer) &&
+		field.elementType == "string"
+	switch {
+	case field.secret && isString:
+		return fmt.Sprintf("gosettings.ObfuscateKey(%s)", value)
+	case field.secret:
+		return fmt.Sprintf("gosettings.ObfuscateKey(fmt.Sprint(%s))", value)
+	case field.kind == kindSlice && field.elementType == "string":
+		return fmt.Sprintf("strings.Join(%s, \", \")", value)
+	case field.kind == kindPointer && field.elementType == "bool":
+		return fmt.Sprintf("gosettings.BoolToYesNo(%s)", strings.TrimPrefix(value, "*"))
+	case isString:
+		return value
+	default:
+		return fmt.Sprintf("fmt.Sprint(%s)", value)
+	}
+}
+
+// nestedStringExpression returns the Go expression converting the
+// nested settings value expression given to indented lines, without
+// the "Type:" header line of the nested settings String method.
+func nestedStringExpression(field fieldInfo, value, indent string) (expression string) {
+	return fmt.Sprintf("strings.ReplaceAll(strings.TrimPrefix(%s.String(), %q), \"\\n\", %q)",
+		value, field.elementType+":", "\n"+indent)
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/qdm12/gosettings/cmd/gosettings-gen/testdata/settings"
+)
+
+func Test_generateFromDirectory(t *testing.T) {
+	t.Parallel()
+
+	directory := filepath.Join("testdata", "settings")
+	const outputFileName = "settings_gosettings.go"
+
+	code, err := generateFromDirectory(directory,
+		[]string{"Settings", "Server"}, outputFileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedCode, err := os.ReadFile(filepath.Join(directory, outputFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(code) != string(expectedCode) {
+		t.Errorf("generated code is different from %s:\n%s", outputFileName, code)
+	}
+}
+
+func Test_generatedCode(t *testing.T) {
+	t.Parallel()
+
+	var original settings.Settings
+	original.SetDefaults()
+	original.Backup = nil
+	original.Server.Port = nil
+
+	err := original.Validate()
+	if err != nil {
+		t.Fatalf("validating settings with nil pointers: %s", err)
+	}
+
+	limit := 1
+	original.Limits = []*int{&limit}
+	original.Replicas = []*settings.Server{{Retries: 1}}
+	original.Regions = map[string]*settings.Server{"eu": {Retries: 1}}
+
+	copied := original.Copy()
+	*copied.Enabled = false
+	copied.Labels["app"] = "changed"
+	*copied.Limits[0] = 2
+	copied.Replicas[0].Retries = 2
+	copied.Regions["eu"].Retries = 2
+	if !*original.Enabled || original.Labels["app"] != "example" ||
+		*original.Limits[0] != 1 || original.Replicas[0].Retries != 1 ||
+		original.Regions["eu"].Retries != 1 {
+		t.Error("copied settings share memory with the original settings")
+	}
+
+	copied.Mode = "other"
+	err = copied.Validate()
+	const expectedErrMessage = "mode: value is not one of the possible choices: " +
+		"other must be one of fast or slow"
+	if err == nil || err.Error() != expectedErrMessage {
+		t.Errorf("expected error %q, got %v", expectedErrMessage, err)
+	}
+}
+
+func Test_generateFromDirectory_errors(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		source     string
+		typeName   string
+		errWrapped error
+		errMessage string
+	}{
+		"type_not_found": {
+			source:     "type Settings struct{}\n",
+			typeName:   "Other",
+			errWrapped: ErrTypeNotFound,
+			errMessage: "struct type not found: Other (known struct types: Settings)",
+		},
+		"embedded_field": {
+			source:     "type Base struct{}\ntype Settings struct{ Base }\n",
+			typeName:   "Settings",
+			errWrapped: ErrFieldTypeUnsupported,
+			errMessage: "field type is not supported: embedded field Base in Settings",
+		},
+		"chan_field": {
+			source:     "type Settings struct{ C chan int }\n",
+			typeName:   "Settings",
+			errWrapped: ErrFieldTypeUnsupported,
+			errMessage: "struct Settings field C: field type is not supported: chan int",
+		},
+		"validate_on_map": {
+			source:     "type Settings struct{ M map[string]int `validate:\"range=1:2\"` }\n",
+			typeName:   "Settings",
+			errWrapped: ErrTagNotValid,
+			errMessage: "struct Settings field M: struct tag is not valid: validate tag on map",
+		},
+		"validate_on_nested_slice": {
+			source:     "type Sub struct{}\ntype Settings struct{ S []Sub `validate:\"oneof=a\"` }\n",
+			typeName:   "Settings",
+			errWrapped: ErrTagNotValid,
+			errMessage: "struct Settings field S: struct tag is not valid: validate tag on nested settings",
+		},
+		"default_not_expression": {
+			source:     "type Settings struct{ N int `default:\"1 +\"` }\n",
+			typeName:   "Settings",
+			errWrapped: ErrTagNotValid,
+			errMessage: "struct Settings field N: struct tag is not valid: " +
+				"default value \"1 +\" is not a Go expression",
+		},
+		"range_on_slice": {
+			source:     "type Settings struct{ N []int `validate:\"range=1:2\"` }\n",
+			typeName:   "Settings",
+			errWrapped: ErrTagNotValid,
+			errMessage: "struct Settings field N: struct tag is not valid: validate: range=1:2",
+		},
+		"unknown_validation": {
+			source:     "type Settings struct{ N int `validate:\"min=1\"` }\n",
+			typeName:   "Settings",
+			errWrapped: ErrTagNotValid,
+			errMessage: "struct Settings field N: struct tag is not valid: validate: min=1",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			directory := t.TempDir()
+			path := filepath.Join(directory, "settings.go")
+			err := os.WriteFile(path, []byte("package settings\n\n"+testCase.source), 0600)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = generateFromDirectory(directory, []string{testCase.typeName}, "out.go")
+
+			if !errors.Is(err, testCase.errWrapped) {
+				t.Errorf("expected error %v to wrap %v", err, testCase.errWrapped)
+			}
+			if err.Error() != testCase.errMessage {
+				t.Errorf("expected error message %q but got %q", testCase.errMessage, err.Error())
+			}
+		})
+	}
+}
+
+func Test_run(t *testing.T) {
+	t.Parallel()
+
+	t.Run("type_flag_missing", func(t *testing.T) {
+		t.Parallel()
+
+		err := run(nil)
+
+		if !errors.Is(err, ErrFlagMissing) {
+			t.Errorf("expected error %v to wrap %v", err, ErrFlagMissing)
+		}
+	})
+
+	t.Run("write_file", func(t *testing.T) {
+		t.Parallel()
+
+		directory := t.TempDir()
+		const source = "package settings\n\ntype Settings struct{ Name string `default:\"x\"` }\n"
+		err := os.WriteFile(filepath.Join(directory, "settings.go"), []byte(source), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = run([]string{"-type=Settings", "-dir=" + directory})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = os.Stat(filepath.Join(directory, "settings_gosettings.go"))
+		if err != nil {
+			t.Error(err)
+		}
+	})
+}
@@ -0,0 +1,83 @@
+// Command gosettings-gen generates the SetDefaults, Copy, OverrideWith,
+// Validate and String methods of settings structs, using the helper
+// functions of the gosettings package, so no field can be forgotten.
+//
+// It is meant to be used with go generate, for example with:
+//
+//	//go:generate go run github.com/qdm12/gosettings/cmd/gosettings-gen -type=Settings
+//
+// Struct fields can be annotated with the following struct tags:
+//   - `default:"8000"` is the Go expression of the default value of the
+//     field, used in SetDefaults. For a pointer field, it is the value
+//     pointed to, and for a slice or map field, it is the default slice
+//     or map.
+//     Values of string and string pointer fields, including types defined
+//     in the same package as `type Level string`, are quoted automatically.
+//   - `validate:"oneof=a|b"` validates the field value is one of the
+//     values given, separated by `|`. Values of string fields, including
+//     types defined in the same package as `type Level string`, are
+//     quoted automatically, other values are Go expressions. Nil pointer
+//     fields are not validated.
+//   - `validate:"range=1:65535"` validates the field value is between
+//     the two Go expressions given, inclusive.
+//   - `secret:"true"` obfuscates the field value in String.
+//
+// Fields of a struct type defined in the same package, pointers to it
+// or slices of it, are considered as nested settings, and their own
+// methods are called, using the gosettings *Nested and CopySliceDeep
+// functions where needed.
+//
+// The generated Copy method deep copies pointer, slice, map and nested
+// fields, including slice elements and map values which are pointers
+// or nested settings, such as []*int or map[string]*Server. Slice
+// elements and map values which are slices or maps, such as [][]string,
+// are however shallow copied and share memory with the original.
+//
+// Unexported fields are ignored, except by the generated Copy method
+// which starts from a shallow copy of the struct to keep them.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	err := run(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gosettings-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) (err error) {
+	flagSet := flag.NewFlagSet("gosettings-gen", flag.ContinueOnError)
+	typeNames := flagSet.String("type", "", "comma separated list of struct type names (required)")
+	directory := flagSet.String("dir", ".", "directory of the Go package containing the types")
+	output := flagSet.String("output", "", "output file name, defaulting to <first type>_gosettings.go")
+	err = flagSet.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if *typeNames == "" {
+		return fmt.Errorf("%w: -type", ErrFlagMissing)
+	}
+	types := strings.Split(*typeNames, ",")
+
+	if *output == "" {
+		*output = strings.ToLower(types[0]) + "_gosettings.go"
+	}
+
+	code, err := generateFromDirectory(*directory, types, *output)
+	if err != nil {
+		return err
+	}
+
+	const permissions = 0644
+	path := filepath.Join(*directory, *output)
+	return os.WriteFile(path, code, permissions)
+}
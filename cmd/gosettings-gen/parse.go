@@ -0,0 +1,367 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrFlagMissing          = errors.New("flag is missing")
+	ErrPackageNotFound      = errors.New("no Go package found")
+	ErrTypeNotFound         = errors.New("struct type not found")
+	ErrFieldTypeUnsupported = errors.New("field type is not supported")
+	ErrTagNotValid          = errors.New("struct tag is not valid")
+)
+
+type fieldKind uint8
+
+const (
+	// kindComparable is for fields of comparable types,
+	// such as strings, numbers or netip.Addr.
+	kindComparable fieldKind = iota
+	kindPointer
+	kindSlice
+	kindMap
+	// kindNested is for fields of struct types defined
+	// in the same package, which are nested settings.
+	kindNested
+	// kindNestedPointer is for pointer fields to struct types
+	// defined in the same package.
+	kindNestedPointer
+)
+
+type structInfo struct {
+	name   string
+	fields []fieldInfo
+}
+
+type fieldInfo struct {
+	name string
+	kind fieldKind
+	// elementType is the type of the value pointed to for pointers,
+	// of the elements for slices, of the values for maps, and the
+	// type itself otherwise.
+	elementType string
+	// nestedElements is true for slices of struct types defined
+	// in the same package, which are deep copied.
+	nestedElements bool
+	// elementKind is the kind of the elements for slices and of the
+	// values for maps, which is one of kindComparable, kindPointer,
+	// kindNested and kindNestedPointer, and is used to deep copy them.
+	elementKind fieldKind
+	// typeName is the type of the field, such as map[string]*Server.
+	typeName     string
+	defaultValue string
+	oneOf        []string
+	rangeMin     string
+	rangeMax     string
+	secret       bool
+}
+
+// parsedPackage contains the package name, files and imports
+// parsed from a directory.
+type parsedPackage struct {
+	name  string
+	files []*ast.File
+	// imports maps package names to their import path, for
+	// all the imports of the package files.
+	imports map[string]string
+}
+
+func parseDirectory(directory, outputFileName string) (pkg parsedPackage, err error) {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return pkg, fmt.Errorf("reading directory: %w", err)
+	}
+
+	pkg.imports = make(map[string]string)
+	fileSet := token.NewFileSet()
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") ||
+			strings.HasSuffix(name, "_test.go") || name == outputFileName {
+			continue
+		}
+
+		filePath := filepath.Join(directory, name)
+		file, err := parser.ParseFile(fileSet, filePath, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return pkg, fmt.Errorf("parsing file: %w", err)
+		}
+		pkg.name = file.Name.Name
+		pkg.files = append(pkg.files, file)
+		for _, importSpec := range file.Imports {
+			importPath, _ := strconv.Unquote(importSpec.Path.Value)
+			pkg.imports[importName(importSpec, importPath)] = importPath
+		}
+	}
+
+	if len(pkg.files) == 0 {
+		return pkg, fmt.Errorf("%w: in %s", ErrPackageNotFound, directory)
+	}
+	return pkg, nil
+}
+
+func importName(importSpec *ast.ImportSpec, importPath string) (name string) {
+	if importSpec.Name != nil {
+		return importSpec.Name.Name
+	}
+	name = path.Base(importPath)
+	isMajorVersion := regexp.MustCompile(`^v[0-9]+$`).MatchString(name)
+	if isMajorVersion {
+		name = path.Base(path.Dir(importPath))
+	}
+	return name
+}
+
+// structTypes returns a map of all struct type names to their
+// struct type expression, for the files given.
+func structTypes(files []*ast.File) (nameToStruct map[string]*ast.StructType) {
+	nameToStruct = make(map[string]*ast.StructType)
+	for _, file := range files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			typeSpec, ok := node.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if ok {
+				nameToStruct[typeSpec.Name.Name] = structType
+			}
+			return false
+		})
+	}
+	return nameToStruct
+}
+
+// stringTypes returns the names of all the types with the
+// underlying type string, such as `type Level string`, for the
+// files given.
+func stringTypes(files []*ast.File) (names map[string]struct{}) {
+	names = make(map[string]struct{})
+	for _, file := range files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			typeSpec, ok := node.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+			ident, ok := typeSpec.Type.(*ast.Ident)
+			if ok && ident.Name == "string" {
+				names[typeSpec.Name.Name] = struct{}{}
+			}
+			return false
+		})
+	}
+	return names
+}
+
+func parseStruct(name string, nameToStruct map[string]*ast.StructType,
+	stringTypeNames map[string]struct{}) (info structInfo, err error) {
+	structType, ok := nameToStruct[name]
+	if !ok {
+		knownNames := make([]string, 0, len(nameToStruct))
+		for knownName := range nameToStruct {
+			knownNames = append(knownNames, knownName)
+		}
+		sort.Strings(knownNames)
+		return info, fmt.Errorf("%w: %s (known struct types: %s)",
+			ErrTypeNotFound, name, strings.Join(knownNames, ", "))
+	}
+
+	info.name = name
+	for _, field := range structType.Fields.List {
+		if len(field.Names) == 0 {
+			return info, fmt.Errorf("%w: embedded field %s in %s",
+				ErrFieldTypeUnsupported, exprString(field.Type), name)
+		}
+
+		for _, fieldName := range field.Names {
+			if !fieldName.IsExported() {
+				continue
+			}
+			fieldInfo, err := parseField(fieldName.Name, field, nameToStruct, stringTypeNames)
+			if err != nil {
+				return info, fmt.Errorf("struct %s field %s: %w", name, fieldName.Name, err)
+			}
+			info.fields = append(info.fields, fieldInfo)
+		}
+	}
+	return info, nil
+}
+
+func parseField(name string, field *ast.Field, nameToStruct map[string]*ast.StructType,
+	stringTypeNames map[string]struct{}) (info fieldInfo, err error) {
+	info.name = name
+	info.typeName = exprString(field.Type)
+
+	switch fieldType := field.Type.(type) {
+	case *ast.StarExpr:
+		info.kind = kindPointer
+		info.elementType = exprString(fieldType.X)
+		if isLocalStruct(fieldType.X, nameToStruct) {
+			info.kind = kindNestedPointer
+		}
+	case *ast.ArrayType:
+		if fieldType.Len != nil {
+			info.kind = kindComparable
+			info.elementType = exprString(fieldType)
+			break
+		}
+		info.kind = kindSlice
+		info.elementType = exprString(fieldType.Elt)
+		info.nestedElements = isLocalStruct(fieldType.Elt, nameToStruct)
+		info.elementKind = elementKind(fieldType.Elt, nameToStruct)
+	case *ast.MapType:
+		info.kind = kindMap
+		info.elementType = exprString(fieldType.Value)
+		info.elementKind = elementKind(fieldType.Value, nameToStruct)
+	case *ast.FuncType, *ast.ChanType, *ast.InterfaceType, *ast.StructType:
+		return info, fmt.Errorf("%w: %s", ErrFieldTypeUnsupported, exprString(fieldType))
+	default:
+		info.elementType = exprString(fieldType)
+		if isLocalStruct(fieldType, nameToStruct) {
+			info.kind = kindNested
+		} else {
+			info.kind = kindComparable
+		}
+	}
+
+	if field.Tag == nil {
+		return info, nil
+	}
+	tagString, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return info, fmt.Errorf("%w: %s", ErrTagNotValid, field.Tag.Value)
+	}
+	_, isStringType := stringTypeNames[info.elementType]
+	isStringType = isStringType || info.elementType == "string"
+	return parseTags(info, reflect.StructTag(tagString), isStringType)
+}
+
+// elementKind returns the kind of a slice element or map value
+// type expression. Slice and map types are returned as
+// kindComparable, since they are not copied element-wise.
+func elementKind(expr ast.Expr, nameToStruct map[string]*ast.StructType) (kind fieldKind) {
+	starExpr, isPointer := expr.(*ast.StarExpr)
+	switch {
+	case isPointer && isLocalStruct(starExpr.X, nameToStruct):
+		return kindNestedPointer
+	case isPointer:
+		return kindPointer
+	case isLocalStruct(expr, nameToStruct):
+		return kindNested
+	default:
+		return kindComparable
+	}
+}
+
+func isLocalStruct(expr ast.Expr, nameToStruct map[string]*ast.StructType) bool {
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	_, ok = nameToStruct[ident.Name]
+	return ok
+}
+
+// parseTags parses the struct tags of the field, where isStringType
+// is true if the field element type has the underlying type string,
+// in which case its default and oneof validation values are quoted.
+func parseTags(info fieldInfo, tag reflect.StructTag, isStringType bool) (fieldInfo, error) {
+	defaultValue, ok := tag.Lookup("default")
+	switch {
+	case !ok:
+	case (info.kind == kindComparable || info.kind == kindPointer) && isStringType:
+		info.defaultValue = strconv.Quote(defaultValue)
+	default:
+		_, err := parser.ParseExpr(defaultValue)
+		if err != nil {
+			return info, fmt.Errorf("%w: default value %q is not a Go expression",
+				ErrTagNotValid, defaultValue)
+		}
+		info.defaultValue = defaultValue
+	}
+
+	secret := tag.Get("secret")
+	if secret != "" {
+		var err error
+		info.secret, err = strconv.ParseBool(secret)
+		if err != nil {
+			return info, fmt.Errorf("%w: secret: %s", ErrTagNotValid, secret)
+		}
+	}
+
+	validation := tag.Get("validate")
+	if validation == "" {
+		return info, nil
+	}
+	switch {
+	case info.kind == kindNested, info.kind == kindNestedPointer, info.nestedElements:
+		return info, fmt.Errorf("%w: validate tag on nested settings", ErrTagNotValid)
+	case info.kind == kindMap:
+		return info, fmt.Errorf("%w: validate tag on map", ErrTagNotValid)
+	}
+
+	name, value, _ := strings.Cut(validation, "=")
+	switch name {
+	case "oneof":
+		info.oneOf = strings.Split(value, "|")
+		if isStringType {
+			for i, possibility := range info.oneOf {
+				info.oneOf[i] = strconv.Quote(possibility)
+			}
+		}
+	case "range":
+		var found bool
+		info.rangeMin, info.rangeMax, found = strings.Cut(value, ":")
+		if !found || info.kind == kindSlice {
+			return info, fmt.Errorf("%w: validate: %s", ErrTagNotValid, validation)
+		}
+	default:
+		return info, fmt.Errorf("%w: validate: %s", ErrTagNotValid, validation)
+	}
+	return info, nil
+}
+
+func exprString(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return exprString(e.X) + "." + e.Sel.Name
+	case *ast.StarExpr:
+		return "*" + exprString(e.X)
+	case *ast.ArrayType:
+		if e.Len == nil {
+			return "[]" + exprString(e.Elt)
+		}
+		return "[" + exprString(e.Len) + "]" + exprString(e.Elt)
+	case *ast.MapType:
+		return "map[" + exprString(e.Key) + "]" + exprString(e.Value)
+	case *ast.BasicLit:
+		return e.Value
+	case *ast.IndexExpr:
+		return exprString(e.X) + "[" + exprString(e.Index) + "]"
+	case *ast.FuncType:
+		return "func"
+	case *ast.ChanType:
+		return "chan " + exprString(e.Value)
+	case *ast.InterfaceType:
+		return "interface"
+	case *ast.StructType:
+		return "struct"
+	default:
+		return fmt.Sprintf("%T", expr)
+	}
+}
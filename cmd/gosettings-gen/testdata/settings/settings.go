@@ -0,0 +1,33 @@
+package settings
+
+import (
+	"net/netip"
+	"time"
+)
+
+//go:generate go run github.com/qdm12/gosettings/cmd/gosettings-gen -type=Settings,Server
+
+type Settings struct {
+	Enabled  *bool         `default:"true"`
+	LogLevel string        `default:"info" validate:"oneof=debug|info|warn|error"`
+	Mode     Mode          `default:"fast" validate:"oneof=fast|slow"`
+	Timeout  time.Duration `default:"10 * time.Second"`
+	Password *string       `default:"" secret:"true"`
+	Users    []string      `validate:"oneof=alice|bob"`
+	Address  netip.Addr
+	Labels   map[string]string `default:"map[string]string{\"app\": \"example\"}"`
+	Server   Server
+	Backup   *Server
+	Mirrors  []Server
+	Replicas []*Server
+	Limits   []*int
+	Regions  map[string]*Server
+	internal int
+}
+
+type Mode string
+
+type Server struct {
+	Port    *uint16 `default:"8000" validate:"range=1:65535"`
+	Retries int     `default:"3" validate:"range=0:10"`
+}
@@ -0,0 +1,225 @@
+// Code generated by gosettings-gen. DO NOT EDIT.
+
+package settings
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/qdm12/gosettings"
+	"github.com/qdm12/gosettings/validate"
+)
+
+// SetDefaults sets the default values of all unset fields.
+func (s *Settings) SetDefaults() {
+	s.Enabled = gosettings.DefaultPointer(s.Enabled, true)
+	s.LogLevel = gosettings.DefaultComparable(s.LogLevel, "info")
+	s.Mode = gosettings.DefaultComparable(s.Mode, "fast")
+	s.Timeout = gosettings.DefaultComparable(s.Timeout, 10*time.Second)
+	s.Password = gosettings.DefaultPointer(s.Password, "")
+	s.Labels = gosettings.DefaultMap(s.Labels, map[string]string{"app": "example"})
+	s.Server.SetDefaults()
+	s.Backup = gosettings.DefaultNested(s.Backup)
+	for i := range s.Mirrors {
+		s.Mirrors[i].SetDefaults()
+	}
+}
+
+// Copy returns a deep copy of the settings.
+func (s *Settings) Copy() (copied Settings) {
+	copied = *s
+	copied.Enabled = gosettings.CopyPointer(s.Enabled)
+	copied.Password = gosettings.CopyPointer(s.Password)
+	copied.Users = gosettings.CopySlice(s.Users)
+	copied.Labels = gosettings.CopyMap(s.Labels)
+	copied.Server = s.Server.Copy()
+	copied.Backup = gosettings.CopyNested(s.Backup)
+	copied.Mirrors = gosettings.CopySliceDeep(s.Mirrors)
+	if s.Replicas != nil {
+		copied.Replicas = make([]*Server, len(s.Replicas))
+		for i, value := range s.Replicas {
+			copied.Replicas[i] = gosettings.CopyNested(value)
+		}
+	}
+	if s.Limits != nil {
+		copied.Limits = make([]*int, len(s.Limits))
+		for i, value := range s.Limits {
+			copied.Limits[i] = gosettings.CopyPointer(value)
+		}
+	}
+	if s.Regions != nil {
+		copied.Regions = make(map[string]*Server, len(s.Regions))
+		for key, value := range s.Regions {
+			copied.Regions[key] = gosettings.CopyNested(value)
+		}
+	}
+	return copied
+}
+
+// OverrideWith overrides the fields of the settings
+// with the set fields of the other settings.
+func (s *Settings) OverrideWith(other Settings) {
+	s.Enabled = gosettings.OverrideWithPointer(s.Enabled, other.Enabled)
+	s.LogLevel = gosettings.OverrideWithComparable(s.LogLevel, other.LogLevel)
+	s.Mode = gosettings.OverrideWithComparable(s.Mode, other.Mode)
+	s.Timeout = gosettings.OverrideWithComparable(s.Timeout, other.Timeout)
+	s.Password = gosettings.OverrideWithPointer(s.Password, other.Password)
+	s.Users = gosettings.OverrideWithSlice(s.Users, other.Users)
+	s.Address = gosettings.OverrideWithComparable(s.Address, other.Address)
+	s.Labels = gosettings.OverrideWithMap(s.Labels, other.Labels)
+	s.Server.OverrideWith(other.Server)
+	s.Backup = gosettings.OverrideWithNested(s.Backup, other.Backup)
+	s.Mirrors = gosettings.OverrideWithSlice(s.Mirrors, other.Mirrors)
+	s.Replicas = gosettings.OverrideWithSlice(s.Replicas, other.Replicas)
+	s.Limits = gosettings.OverrideWithSlice(s.Limits, other.Limits)
+	s.Regions = gosettings.OverrideWithMap(s.Regions, other.Regions)
+}
+
+// Validate validates the settings and returns an error
+// if one setting is not valid. It should be called after
+// SetDefaults, and nil pointer fields are not validated.
+func (s *Settings) Validate() (err error) {
+	err = validate.IsOneOf(s.LogLevel, "debug", "info", "warn", "error")
+	if err != nil {
+		return fmt.Errorf("logLevel: %w", err)
+	}
+
+	err = validate.IsOneOf(s.Mode, "fast", "slow")
+	if err != nil {
+		return fmt.Errorf("mode: %w", err)
+	}
+
+	err = validate.AreAllOneOf(s.Users, []string{"alice", "bob"})
+	if err != nil {
+		return fmt.Errorf("users: %w", err)
+	}
+
+	err = s.Server.Validate()
+	if err != nil {
+		return fmt.Errorf("server: %w", err)
+	}
+
+	if s.Backup != nil {
+		err = s.Backup.Validate()
+		if err != nil {
+			return fmt.Errorf("backup: %w", err)
+		}
+	}
+
+	for i := range s.Mirrors {
+		err = s.Mirrors[i].Validate()
+		if err != nil {
+			return fmt.Errorf("mirrors %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// String returns a string representation of the settings.
+func (s Settings) String() string {
+	builder := new(strings.Builder)
+	builder.WriteString("Settings:")
+	builder.WriteString("\n- Enabled: ")
+	if s.Enabled == nil {
+		builder.WriteString("[not set]")
+	} else {
+		builder.WriteString(gosettings.BoolToYesNo(s.Enabled))
+	}
+	builder.WriteString("\n- LogLevel: ")
+	builder.WriteString(s.LogLevel)
+	builder.WriteString("\n- Mode: ")
+	builder.WriteString(fmt.Sprint(s.Mode))
+	builder.WriteString("\n- Timeout: ")
+	builder.WriteString(fmt.Sprint(s.Timeout))
+	builder.WriteString("\n- Password: ")
+	if s.Password == nil {
+		builder.WriteString("[not set]")
+	} else {
+		builder.WriteString(gosettings.ObfuscateKey(*s.Password))
+	}
+	builder.WriteString("\n- Users: ")
+	builder.WriteString(strings.Join(s.Users, ", "))
+	builder.WriteString("\n- Address: ")
+	builder.WriteString(fmt.Sprint(s.Address))
+	builder.WriteString("\n- Labels: ")
+	builder.WriteString(fmt.Sprint(s.Labels))
+	builder.WriteString("\n- Server:")
+	builder.WriteString(strings.ReplaceAll(strings.TrimPrefix(s.Server.String(), "Server:"), "\n", "\n  "))
+	builder.WriteString("\n- Backup:")
+	if s.Backup == nil {
+		builder.WriteString(" [not set]")
+	} else {
+		builder.WriteString(strings.ReplaceAll(strings.TrimPrefix(s.Backup.String(), "Server:"), "\n", "\n  "))
+	}
+	builder.WriteString("\n- Mirrors:")
+	if len(s.Mirrors) == 0 {
+		builder.WriteString(" [not set]")
+	}
+	for i := range s.Mirrors {
+		builder.WriteString(fmt.Sprintf("\n  - %d:", i+1))
+		builder.WriteString(strings.ReplaceAll(strings.TrimPrefix(s.Mirrors[i].String(), "Server:"), "\n", "\n    "))
+	}
+	builder.WriteString("\n- Replicas: ")
+	builder.WriteString(fmt.Sprint(s.Replicas))
+	builder.WriteString("\n- Limits: ")
+	builder.WriteString(fmt.Sprint(s.Limits))
+	builder.WriteString("\n- Regions: ")
+	builder.WriteString(fmt.Sprint(s.Regions))
+	return builder.String()
+}
+
+// SetDefaults sets the default values of all unset fields.
+func (s *Server) SetDefaults() {
+	s.Port = gosettings.DefaultPointer(s.Port, 8000)
+	s.Retries = gosettings.DefaultComparable(s.Retries, 3)
+}
+
+// Copy returns a deep copy of the settings.
+func (s *Server) Copy() (copied Server) {
+	copied = *s
+	copied.Port = gosettings.CopyPointer(s.Port)
+	return copied
+}
+
+// OverrideWith overrides the fields of the settings
+// with the set fields of the other settings.
+func (s *Server) OverrideWith(other Server) {
+	s.Port = gosettings.OverrideWithPointer(s.Port, other.Port)
+	s.Retries = gosettings.OverrideWithComparable(s.Retries, other.Retries)
+}
+
+// Validate validates the settings and returns an error
+// if one setting is not valid. It should be called after
+// SetDefaults, and nil pointer fields are not validated.
+func (s *Server) Validate() (err error) {
+	if s.Port != nil {
+		err = validate.NumberBetween(*s.Port, 1, 65535)
+		if err != nil {
+			return fmt.Errorf("port: %w", err)
+		}
+	}
+
+	err = validate.NumberBetween(s.Retries, 0, 10)
+	if err != nil {
+		return fmt.Errorf("retries: %w", err)
+	}
+
+	return nil
+}
+
+// String returns a string representation of the settings.
+func (s Server) String() string {
+	builder := new(strings.Builder)
+	builder.WriteString("Server:")
+	builder.WriteString("\n- Port: ")
+	if s.Port == nil {
+		builder.WriteString("[not set]")
+	} else {
+		builder.WriteString(fmt.Sprint(*s.Port))
+	}
+	builder.WriteString("\n- Retries: ")
+	builder.WriteString(fmt.Sprint(s.Retries))
+	return builder.String()
+}
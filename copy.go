@@ -1,6 +1,7 @@
 package gosettings
 
 import (
+	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 )
 
@@ -20,3 +21,40 @@ func CopyPointer[T any](original *T) (copied *T) {
 func CopySlice[T any](original []T) (copied []T) {
 	return slices.Clone(original)
 }
+
+// CopyMap returns a new map with each key and value of the
+// original map copied. It returns nil if the original map is nil.
+func CopyMap[K comparable, V any](original map[K]V) (copied map[K]V) {
+	return maps.Clone(original)
+}
+
+// CopyNested returns a new pointer to a deep copy of the original
+// value pointed to, using its Copy method.
+// It returns nil if the original pointer is nil.
+func CopyNested[T any, P interface {
+	*T
+	Copier[T]
+}](original P) (copied P) {
+	if original == nil {
+		return nil
+	}
+	value := original.Copy()
+	return &value
+}
+
+// CopySliceDeep returns a new slice with each element of the
+// original slice deep copied using its Copy method.
+// It returns nil if the original slice is nil.
+func CopySliceDeep[T any, P interface {
+	*T
+	Copier[T]
+}](original []T) (copied []T) {
+	if original == nil {
+		return nil
+	}
+	copied = make([]T, len(original))
+	for i := range original {
+		copied[i] = P(&original[i]).Copy()
+	}
+	return copied
+}
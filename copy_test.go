@@ -0,0 +1,101 @@
+package gosettings
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_CopyMap(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		original map[string]int
+	}{
+		"nil": {},
+		"empty": {
+			original: map[string]int{},
+		},
+		"non_empty": {
+			original: map[string]int{"a": 1, "b": 2},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			copied := CopyMap(testCase.original)
+
+			if !reflect.DeepEqual(copied, testCase.original) {
+				t.Fatalf("expected %v, got %v", testCase.original, copied)
+			}
+			if copied != nil {
+				copied["mutated"] = 0
+				if _, ok := testCase.original["mutated"]; ok {
+					t.Error("original map was mutated through copied map")
+				}
+			}
+		})
+	}
+}
+
+func Test_CopyNested(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil", func(t *testing.T) {
+		t.Parallel()
+
+		copied := CopyNested((*testNested)(nil))
+		if copied != nil {
+			t.Errorf("expected nil, got %v", copied)
+		}
+	})
+
+	t.Run("deep_copy", func(t *testing.T) {
+		t.Parallel()
+
+		original := &testNested{Name: "a", Values: []int{1, 2}}
+
+		copied := CopyNested(original)
+
+		expected := &testNested{Name: "a", Values: []int{1, 2}}
+		if !reflect.DeepEqual(copied, expected) {
+			t.Fatalf("expected %v, got %v", expected, copied)
+		}
+		copied.Values[0] = 9
+		if original.Values[0] != 1 {
+			t.Error("original slice was mutated through copied value")
+		}
+	})
+}
+
+func Test_CopySliceDeep(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil", func(t *testing.T) {
+		t.Parallel()
+
+		copied := CopySliceDeep([]testNested(nil))
+		if copied != nil {
+			t.Errorf("expected nil, got %v", copied)
+		}
+	})
+
+	t.Run("deep_copy", func(t *testing.T) {
+		t.Parallel()
+
+		original := []testNested{{Name: "a", Values: []int{1}}, {Name: "b"}}
+
+		copied := CopySliceDeep(original)
+
+		expected := []testNested{{Name: "a", Values: []int{1}}, {Name: "b"}}
+		if !reflect.DeepEqual(copied, expected) {
+			t.Fatalf("expected %v, got %v", expected, copied)
+		}
+		copied[0].Values[0] = 9
+		if original[0].Values[0] != 1 {
+			t.Error("original slice was mutated through copied slice")
+		}
+	})
+}
@@ -44,6 +44,38 @@ func DefaultSlice[T any](existing, defaultValue []T) (result []T) {
 	return result
 }
 
+// DefaultMap returns the existing map argument if is not nil.
+// Otherwise it returns a new map with the copied keys and values
+// of the defaultValue map argument.
+// Note an empty non-nil existing map is kept as is.
+func DefaultMap[K comparable, V any](existing, defaultValue map[K]V) (
+	result map[K]V) {
+	if existing != nil || defaultValue == nil {
+		return existing
+	}
+	result = make(map[K]V, len(defaultValue))
+	for key, value := range defaultValue {
+		result[key] = value
+	}
+	return result
+}
+
+// DefaultNested sets the defaults of the existing value pointed to
+// using its SetDefaults method, and returns the existing pointer.
+// If the existing pointer is nil, it returns a new pointer to
+// a zero value with its defaults set.
+// Note the existing value is modified in place.
+func DefaultNested[T any, P interface {
+	*T
+	Defaulter
+}](existing P) (result P) {
+	if existing == nil {
+		existing = new(T)
+	}
+	existing.SetDefaults()
+	return existing
+}
+
 // DefaultValidator returns the existing argument if it is valid,
 // otherwise it returns the defaultValue argument.
 func DefaultValidator[T SelfValidator](existing, defaultValue T) ( //nolint:ireturn
@@ -2,6 +2,7 @@ package gosettings
 
 import (
 	"net/netip"
+	"reflect"
 	"testing"
 )
 
@@ -95,3 +96,83 @@ func Test_DefaultValidator(t *testing.T) {
 		})
 	}
 }
+
+func Test_DefaultMap(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		existing     map[string]int
+		defaultValue map[string]int
+		result       map[string]int
+	}{
+		"all_nil": {},
+		"nil_existing_uses_default": {
+			defaultValue: map[string]int{"a": 1},
+			result:       map[string]int{"a": 1},
+		},
+		"empty_existing_is_kept": {
+			existing:     map[string]int{},
+			defaultValue: map[string]int{"a": 1},
+			result:       map[string]int{},
+		},
+		"existing_is_kept": {
+			existing:     map[string]int{"b": 2},
+			defaultValue: map[string]int{"a": 1},
+			result:       map[string]int{"b": 2},
+		},
+		"nil_existing_empty_default": {
+			defaultValue: map[string]int{},
+			result:       map[string]int{},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result := DefaultMap(testCase.existing, testCase.defaultValue)
+
+			if !reflect.DeepEqual(result, testCase.result) {
+				t.Fatalf("expected %v, got %v", testCase.result, result)
+			}
+			if testCase.existing == nil && result != nil {
+				result["mutated"] = 0
+				if _, ok := testCase.defaultValue["mutated"]; ok {
+					t.Error("default map was mutated through result map")
+				}
+			}
+		})
+	}
+}
+
+func Test_DefaultNested(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil_existing", func(t *testing.T) {
+		t.Parallel()
+
+		result := DefaultNested((*testNested)(nil))
+
+		expected := &testNested{Name: "default", Values: []int{0}}
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("expected %v, got %v", expected, result)
+		}
+	})
+
+	t.Run("existing", func(t *testing.T) {
+		t.Parallel()
+
+		existing := &testNested{Name: "a"}
+
+		result := DefaultNested(existing)
+
+		if result != existing {
+			t.Error("expected existing pointer to be returned")
+		}
+		expected := &testNested{Name: "a", Values: []int{0}}
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("expected %v, got %v", expected, result)
+		}
+	})
+}
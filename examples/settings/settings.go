@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/qdm12/gosettings"
+	"github.com/qdm12/gosettings/tree"
 )
 
 // Settings holds all the settings.
@@ -51,9 +52,15 @@ func (s *Settings) OverrideWith(other Settings) {
 	s.Names = gosettings.OverrideWithSlice(s.Names, other.Names)
 }
 
+// ToLinesNode returns a tree node with the settings as lines.
+func (s Settings) ToLinesNode() *tree.Node {
+	node := tree.New("Settings:")
+	node.AppendField("Enabled", s.Enabled)
+	node.AppendField("Names", s.Names)
+	return node
+}
+
 // String returns a string representation of the settings.
 func (s Settings) String() string {
-	return fmt.Sprintf(`Settings:
-	- Enabled: %t
-	- Names: %s`, *s.Enabled, strings.Join(s.Names, ", "))
+	return s.ToLinesNode().String()
 }
@@ -1,5 +1,7 @@
 package gosettings
 
+import "errors"
+
 func ptrTo[T any](t T) *T { return &t }
 
 type testInterface interface {
@@ -13,3 +15,34 @@ func (testInterfaceImplA) F() {}
 type testInterfaceImplB struct{}
 
 func (testInterfaceImplB) F() {}
+
+type testNested struct {
+	Name   string
+	Values []int
+}
+
+func (n *testNested) Copy() (copied testNested) {
+	return testNested{
+		Name:   n.Name,
+		Values: CopySlice(n.Values),
+	}
+}
+
+func (n *testNested) OverrideWith(other testNested) {
+	n.Name = OverrideWithComparable(n.Name, other.Name)
+	n.Values = OverrideWithSlice(n.Values, other.Values)
+}
+
+func (n *testNested) SetDefaults() {
+	n.Name = DefaultComparable(n.Name, "default")
+	n.Values = DefaultSlice(n.Values, []int{0})
+}
+
+var errTestNameNotValid = errors.New("name is not valid")
+
+func (n *testNested) Validate() (err error) {
+	if n.Name == "invalid" {
+		return errTestNameNotValid
+	}
+	return nil
+}
@@ -7,3 +7,28 @@ type SelfValidator interface {
 	// IsValid returns true if the value is valid, false otherwise.
 	IsValid() bool
 }
+
+// Copier is an interface for a type which can deep copy itself,
+// typically implemented by a settings struct with a pointer receiver
+// `func (s *Settings) Copy() (copied Settings)`.
+type Copier[T any] interface {
+	// Copy returns a deep copy of the value.
+	Copy() T
+}
+
+// OverrideWither is an interface for a type which can override
+// its fields with the set fields of another value of the same type,
+// typically implemented by a settings struct with a pointer receiver
+// `func (s *Settings) OverrideWith(other Settings)`.
+type OverrideWither[T any] interface {
+	// OverrideWith overrides the fields of the receiver
+	// with the set fields of the other value.
+	OverrideWith(other T)
+}
+
+// Defaulter is an interface for a type which can set
+// default values for its unset fields.
+type Defaulter interface {
+	// SetDefaults sets the default values of unset fields.
+	SetDefaults()
+}
@@ -0,0 +1,150 @@
+// Package flatten provides functions to flatten nested values
+// decoded from structured files into a map of flat keys to
+// string values, to be used by file settings sources.
+package flatten
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrKeyConflict       = errors.New("flattened key conflict")
+	ErrArrayElementComma = errors.New("array element contains a comma")
+)
+
+// Flatten flattens the object given into a map of keys to string values.
+// Nested keys are joined with underscores and normalized with NormalizeKey.
+// Values are converted to strings such that:
+//   - strings are kept as they are
+//   - nil values are considered as unset and are not in the map returned
+//   - arrays of non-object and non-array values are converted to
+//     comma separated values, to be used with the CSV methods.
+//     Since commas cannot be escaped, an error is returned if an
+//     element of such array contains a comma
+//   - other arrays are flattened using the element index as key,
+//     for example SERVERS_0_ADDRESS
+//   - other values are formatted using fmt.Sprint.
+//
+// An error is returned if two different keys result in the same
+// flattened key, or if an element of an array converted to comma
+// separated values contains a comma.
+func Flatten(object map[string]any) (keyToValue map[string]string, err error) {
+	keyToValue = make(map[string]string)
+	keyToPath := make(map[string]string)
+	for key, value := range object {
+		err = flatten(key, key, value, keyToValue, keyToPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return keyToValue, nil
+}
+
+// NormalizeKey normalizes a key by:
+// - Changing all characters to be uppercase
+// - Replacing all dashes, dots and spaces with underscores.
+func NormalizeKey(key string) (newKey string) {
+	newKey = strings.ToUpper(key)
+	newKey = strings.NewReplacer("-", "_", ".", "_", " ", "_").Replace(newKey)
+	return newKey
+}
+
+// flatten flattens the value given into the keyToValue map,
+// where key is the flattened key prefix and path is the
+// path used for error messages. The keyToPath map is used to
+// detect conflicting flattened keys.
+func flatten(key, path string, value any,
+	keyToValue, keyToPath map[string]string) (err error) {
+	switch typedValue := value.(type) {
+	case map[string]any:
+		for childKey, childValue := range typedValue {
+			err = flatten(key+"_"+childKey, path+"."+childKey, childValue,
+				keyToValue, keyToPath)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case []any:
+		if !isFlatArray(typedValue) {
+			for i, element := range typedValue {
+				index := fmt.Sprint(i)
+				err = flatten(key+"_"+index, path+"["+index+"]", element,
+					keyToValue, keyToPath)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		err = checkNoComma(path, typedValue)
+		if err != nil {
+			return err
+		}
+	case nil:
+		return nil
+	}
+
+	key = NormalizeKey(key)
+	existingPath, exists := keyToPath[key]
+	if exists {
+		// Sort paths for a deterministic error message.
+		first, second := existingPath, path
+		if first > second {
+			first, second = second, first
+		}
+		return fmt.Errorf("%w: %s and %s both map to %s",
+			ErrKeyConflict, first, second, key)
+	}
+	keyToPath[key] = path
+	keyToValue[key] = toString(value)
+	return nil
+}
+
+// isFlatArray returns true if the array does not contain
+// any object or array.
+func isFlatArray(array []any) (flat bool) {
+	for _, element := range array {
+		switch element.(type) {
+		case map[string]any, []any:
+			return false
+		}
+	}
+	return true
+}
+
+// checkNoComma returns an error if an element of the array given
+// contains a comma, since it would be split into multiple values
+// by the CSV parsing methods.
+func checkNoComma(path string, array []any) (err error) {
+	for i, element := range array {
+		if element == nil {
+			continue
+		}
+		s := toString(element)
+		if strings.Contains(s, ",") {
+			return fmt.Errorf("%w: %s[%d] is %q", ErrArrayElementComma, path, i, s)
+		}
+	}
+	return nil
+}
+
+func toString(value any) (s string) {
+	switch typedValue := value.(type) {
+	case string:
+		return typedValue
+	case []any:
+		elements := make([]string, 0, len(typedValue))
+		for _, element := range typedValue {
+			if element == nil {
+				continue
+			}
+			elements = append(elements, toString(element))
+		}
+		return strings.Join(elements, ",")
+	default:
+		return fmt.Sprint(typedValue)
+	}
+}
@@ -1,7 +1,7 @@
 package parse
 
 import (
-	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -15,14 +15,14 @@ import (
 //   - Force lowercase.
 //
 // If the key is not set in any of the sources, the empty string is
-// returned.
+// returned. An error is returned in the same cases as the Get function.
 func String(sources []Source, key string,
-	options ...Option) (value string) {
-	s, _ := get(sources, key, options...)
+	options ...Option) (value string, err error) {
+	s, err := Get(sources, key, options...)
 	if s == nil {
-		return ""
+		return "", err
 	}
-	return *s
+	return *s, err
 }
 
 // CSV returns a slice of strings from the first comma separated
@@ -39,19 +39,69 @@ func String(sources []Source, key string,
 //   - the key given is NOT set in any of the sources.
 //   - By default and unless changed by the AcceptEmpty option,
 //     if the key is set and its corresponding value is empty.
+//
+// An error is returned in the same cases as the Get function.
 func CSV(sources []Source, key string,
-	options ...Option) (values []string) {
-	csv, _ := csv(sources, key, options...)
-	return csv
+	options ...Option) (values []string, err error) {
+	values, origin, err := csv(sources, key, options...)
+	if err != nil {
+		return values, origin.wrapError(key, err)
+	}
+	return values, nil
 }
 
 func csv(sources []Source, key string,
-	options ...Option) (values []string, sourceName string) {
-	csv, sourceName := get(sources, key, options...)
+	options ...Option) (values []string, origin origin, err error) {
+	// The observer is called here with the concatenated values,
+	// instead of with the value of the last occurrence of the key.
+	observe := settingsFromOptions(options).observe
+	if observe != nil {
+		defer func() {
+			var value *string
+			if values != nil {
+				value = new(string)
+				*value = strings.Join(values, ",")
+			}
+			observe(key, newLookupResult(value, origin))
+		}()
+	}
+
+	options = append(slices.Clip(options), multiValues(), Observe(nil))
+	csv, origin, source, err := getWithSource(sources, key, options...)
 	if csv == nil {
-		return nil, sourceName
+		return nil, origin, err
+	}
+
+	multiGetter, ok := source.(MultiGetter)
+	if !ok {
+		return strings.Split(*csv, ","), origin, err
 	}
-	return strings.Split(*csv, ","), sourceName
+	rawValues := multiGetter.GetAll(origin.key)
+	const minOccurrences = 2
+	if len(rawValues) < minOccurrences {
+		return strings.Split(*csv, ","), origin, err
+	}
+
+	// Concatenate the comma separated values of each occurrence
+	// of the key in the source, for example a repeated flag.
+	// Note the value returned by getWithSource is the one of the
+	// last occurrence, which is processed again below.
+	settings := settingsFromOptions(options)
+	err = nil
+	usedRawValues := make([]string, 0, len(rawValues))
+	for _, rawValue := range rawValues {
+		value, processErr := processValue(sources, rawValue, settings, key)
+		if processErr != nil && err == nil {
+			err = processErr
+		}
+		if value == "" && !*settings.acceptEmpty {
+			continue
+		}
+		usedRawValues = append(usedRawValues, rawValue)
+		values = append(values, strings.Split(value, ",")...)
+	}
+	origin.rawValue = strings.Join(usedRawValues, ",")
+	return values, origin, err
 }
 
 // GetParse parses the first value found at the given key
@@ -75,14 +125,16 @@ func csv(sources []Source, key string,
 //   - Force lowercase.
 func GetParse[T any](sources []Source, key string, //nolint:ireturn
 	parse ParseFunc[T], options ...Option) (value T, err error) {
-	s, sourceKind := get(sources, key, options...)
-	if s == nil {
+	s, origin, err := get(sources, key, options...)
+	if err != nil {
+		return value, origin.wrapError(key, err)
+	} else if s == nil {
 		return value, nil
 	}
 
 	value, err = parse(*s)
 	if err != nil {
-		return value, fmt.Errorf("%s %s: %w", sourceKind, key, err)
+		return value, origin.wrapError(key, err)
 	}
 
 	return value, nil
@@ -108,15 +160,17 @@ func GetParse[T any](sources []Source, key string, //nolint:ireturn
 //   - Force lowercase.
 func GetParsePtr[T any](sources []Source, key string,
 	parse ParseFunc[T], options ...Option) (value *T, err error) {
-	s, sourceKind := get(sources, key, options...)
-	if s == nil {
+	s, origin, err := get(sources, key, options...)
+	if err != nil {
+		return nil, origin.wrapError(key, err)
+	} else if s == nil {
 		return nil, nil //nolint:nilnil
 	}
 
 	value = new(T)
 	*value, err = parse(*s)
 	if err != nil {
-		return nil, fmt.Errorf("%s %s: %w", sourceKind, key, err)
+		return nil, origin.wrapError(key, err)
 	}
 
 	return value, nil
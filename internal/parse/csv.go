@@ -1,9 +1,5 @@
 package parse
 
-import (
-	"fmt"
-)
-
 // CSVParse returns a slice of type T from the first comma separated
 // string value found at the given key in the given sources in order.
 // Each comma separated values is parsed using the provided
@@ -14,8 +10,10 @@ import (
 //     if the key is set and the corresponding value is empty.
 func CSVParse[T any](sources []Source, key string,
 	parse ParseFunc[T], options ...Option) (values []T, err error) {
-	stringValues, sourceName := csv(sources, key, options...)
-	if stringValues == nil {
+	stringValues, origin, err := csv(sources, key, options...)
+	if err != nil {
+		return nil, origin.wrapError(key, err)
+	} else if stringValues == nil {
 		return nil, nil
 	}
 
@@ -23,7 +21,7 @@ func CSVParse[T any](sources []Source, key string,
 	for i, stringValue := range stringValues {
 		values[i], err = parse(stringValue)
 		if err != nil {
-			return nil, fmt.Errorf("%s %s: %w", sourceName, key, err)
+			return nil, origin.wrapError(key, err)
 		}
 	}
 
@@ -0,0 +1,144 @@
+package parse
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testMultiSource struct {
+	keyToValues map[string][]string
+}
+
+func (t *testMultiSource) String() string { return "multi" }
+
+func (t *testMultiSource) Get(key string) (value string, isSet bool) {
+	values, isSet := t.keyToValues[key]
+	if !isSet {
+		return "", false
+	}
+	return values[len(values)-1], true
+}
+
+func (t *testMultiSource) GetAll(key string) (values []string) {
+	return t.keyToValues[key]
+}
+
+func (t *testMultiSource) KeyTransform(key string) string { return key }
+
+func Test_CSV(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		sources []Source
+		key     string
+		options []Option
+		values  []string
+	}{
+		"not_set": {
+			sources: []Source{&testMultiSource{}},
+			key:     "KEY",
+		},
+		"single_value": {
+			sources: []Source{&testMultiSource{keyToValues: map[string][]string{
+				"KEY": {"A,b"},
+			}}},
+			key:    "KEY",
+			values: []string{"a", "b"},
+		},
+		"repeated_values": {
+			sources: []Source{&testMultiSource{keyToValues: map[string][]string{
+				"KEY": {"a,b", " C ", "", "d"},
+			}}},
+			key:    "KEY",
+			values: []string{"a", "b", "c", "d"},
+		},
+		"repeated_values_last_empty": {
+			sources: []Source{
+				&testMultiSource{keyToValues: map[string][]string{
+					"KEY": {"a", ""},
+				}},
+				&testMapSource{keyToValue: map[string]string{
+					"KEY": "b",
+				}},
+			},
+			key:    "KEY",
+			values: []string{"a"},
+		},
+		"repeated_values_all_empty": {
+			sources: []Source{
+				&testMultiSource{keyToValues: map[string][]string{
+					"KEY": {"", ""},
+				}},
+				&testMapSource{keyToValue: map[string]string{
+					"KEY": "b",
+				}},
+			},
+			key:    "KEY",
+			values: []string{"b"},
+		},
+		"repeated_values_accept_empty": {
+			sources: []Source{&testMultiSource{keyToValues: map[string][]string{
+				"KEY": {"a", ""},
+			}}},
+			key:     "KEY",
+			options: []Option{AcceptEmpty(true)},
+			values:  []string{"a", ""},
+		},
+		"repeated_values_retro_key": {
+			sources: []Source{&testMultiSource{keyToValues: map[string][]string{
+				"OLD": {"a", "b"},
+				"KEY": {"c"},
+			}}},
+			key:     "KEY",
+			options: []Option{RetroKeys(func(_, _, _ string) {}, "OLD")},
+			values:  []string{"a", "b"},
+		},
+		"source_without_multiple_values": {
+			sources: []Source{&testMapSource{keyToValue: map[string]string{
+				"KEY": "a,b",
+			}}},
+			key:    "KEY",
+			values: []string{"a", "b"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			values, err := CSV(testCase.sources, testCase.key, testCase.options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(values, testCase.values) {
+				t.Errorf("expected %#v, got %#v", testCase.values, values)
+			}
+		})
+	}
+}
+
+func Test_CSVUint16_repeated(t *testing.T) {
+	t.Parallel()
+
+	sources := []Source{&testMultiSource{keyToValues: map[string][]string{
+		"PORTS": {"80,443", "8000"},
+		"BAD":   {"1", "x"},
+	}}}
+
+	ports, err := CSVUint16(sources, "PORTS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedPorts := []uint16{80, 443, 8000}
+	if !reflect.DeepEqual(ports, expectedPorts) {
+		t.Errorf("expected %v, got %v", expectedPorts, ports)
+	}
+
+	_, err = CSVUint16(sources, "BAD")
+	const expectedErrMessage = `multi BAD: strconv.ParseUint: parsing "x": invalid syntax`
+	if err == nil || err.Error() != expectedErrMessage {
+		t.Errorf("expected error %q, got %v", expectedErrMessage, err)
+	}
+}
@@ -0,0 +1,131 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrExpansionNotClosed = errors.New("variable reference is not closed")
+	ErrExpansionKeyEmpty  = errors.New("variable reference key is empty")
+	ErrExpansionCycle     = errors.New("variable references form a cycle")
+)
+
+// expand expands the `${KEY}` and `${KEY:-default}` references
+// in the value given, resolving each referenced key through the
+// sources in order, and replaces `$$` with `$`.
+// The chain argument is the chain of keys being expanded, and is
+// used to detect reference cycles.
+func expand(sources []Source, value string, settings settings,
+	chain []string) (expanded string, err error) {
+	var builder strings.Builder
+	for {
+		dollarIndex := strings.IndexByte(value, '$')
+		if dollarIndex == -1 || dollarIndex == len(value)-1 {
+			builder.WriteString(value)
+			return builder.String(), nil
+		}
+		builder.WriteString(value[:dollarIndex])
+		value = value[dollarIndex:]
+
+		switch value[1] {
+		case '$':
+			builder.WriteByte('$')
+			value = value[2:]
+			continue
+		case '{':
+		default:
+			builder.WriteByte('$')
+			value = value[1:]
+			continue
+		}
+
+		closingIndex := findClosingBrace(value)
+		if closingIndex == -1 {
+			return "", fmt.Errorf("%w: %s", ErrExpansionNotClosed, value)
+		}
+		reference := value[2:closingIndex]
+		value = value[closingIndex+1:]
+
+		resolved, err := resolveReference(sources, reference, settings, chain)
+		if err != nil {
+			return "", err
+		}
+		builder.WriteString(resolved)
+	}
+}
+
+// findClosingBrace returns the index of the closing brace matching
+// the opening `${` at the start of the value given, taking into
+// account nested references, or -1 if it is not found.
+func findClosingBrace(value string) (index int) {
+	depth := 0
+	for i := 0; i < len(value); i++ {
+		switch {
+		case value[i] == '$' && i+1 < len(value) && value[i+1] == '$':
+			i++
+		case value[i] == '$' && i+1 < len(value) && value[i+1] == '{':
+			depth++
+			i++
+		case value[i] == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// resolveReference resolves a reference of the form `KEY` or
+// `KEY:-default` found within `${` and `}`. The resolved value
+// is not post-processed, since the whole expanded value is.
+func resolveReference(sources []Source, reference string,
+	settings settings, chain []string) (resolved string, err error) {
+	key, defaultValue, hasDefault := strings.Cut(reference, ":-")
+	if key == "" {
+		return "", fmt.Errorf("%w: ${%s}", ErrExpansionKeyEmpty, reference)
+	}
+
+	if slices.Contains(chain, key) {
+		chain = append(slices.Clone(chain), key)
+		return "", fmt.Errorf("%w: %s", ErrExpansionCycle, strings.Join(chain, " -> "))
+	}
+
+	value, isSet, err := firstValue(sources, key, *settings.acceptEmpty)
+	if err != nil {
+		return "", fmt.Errorf("resolving %s: %w", key, err)
+	} else if isSet && value != "" {
+		chain = append(slices.Clone(chain), key)
+		value, err = expand(sources, value, settings, chain)
+		if err != nil {
+			return "", err
+		}
+		return value, nil
+	}
+
+	if !hasDefault {
+		return "", nil
+	}
+	return expand(sources, defaultValue, settings, chain)
+}
+
+// firstValue returns the first raw value set for the key given,
+// through the sources in order. Empty values are skipped unless
+// acceptEmpty is true. An error is returned if a source fails
+// to get the value of the key.
+func firstValue(sources []Source, key string, acceptEmpty bool) (
+	value string, isSet bool, err error) {
+	for _, source := range sources {
+		transformedKey := source.KeyTransform(key)
+		value, isSet, err = getFromSource(source, transformedKey)
+		if err != nil {
+			return "", false, fmt.Errorf("%s %s: %w", source, transformedKey, err)
+		} else if isSet && (acceptEmpty || value != "") {
+			return value, true, nil
+		}
+	}
+	return "", false, nil
+}
@@ -0,0 +1,170 @@
+package parse
+
+import (
+	"errors"
+	"testing"
+)
+
+type testMapSource struct {
+	keyToValue map[string]string
+}
+
+func (t *testMapSource) String() string { return "map" }
+
+func (t *testMapSource) Get(key string) (value string, isSet bool) {
+	value, isSet = t.keyToValue[key]
+	return value, isSet
+}
+
+func (t *testMapSource) KeyTransform(key string) string { return key }
+
+func Test_expand(t *testing.T) {
+	t.Parallel()
+
+	sources := []Source{
+		&testMapSource{keyToValue: map[string]string{
+			"HOST":  "Example.com",
+			"EMPTY": "",
+			"PORT":  "",
+		}},
+		&testMapSource{keyToValue: map[string]string{
+			"PORT":    "8000",
+			"URL":     "http://${HOST}:${PORT}",
+			"QUOTED":  " 'quoted' \n",
+			"CYCLE_A": "${CYCLE_B}",
+			"CYCLE_B": "x${CYCLE_C:-y}",
+			"CYCLE_C": "${CYCLE_A}",
+		}},
+	}
+
+	testCases := map[string]struct {
+		value      string
+		options    []Option
+		expanded   string
+		errWrapped error
+		errMessage string
+	}{
+		"no_reference": {
+			value:    "plain $ value$",
+			expanded: "plain $ value$",
+		},
+		"escaped_dollar": {
+			value:    "$${HOST} $$$$",
+			expanded: "${HOST} $$",
+		},
+		"references": {
+			value:    "${HOST}:${PORT}",
+			expanded: "Example.com:8000",
+		},
+		"no_lowercase": {
+			value:    "${HOST}",
+			options:  []Option{ForceLowercase(false)},
+			expanded: "Example.com",
+		},
+		"nested_references": {
+			value:    "url=${URL}",
+			expanded: "url=http://Example.com:8000",
+		},
+		"reference_not_post_processed": {
+			value:    "[${QUOTED}]",
+			expanded: "[ 'quoted' \n]",
+		},
+		"unset_reference": {
+			value:    "a${MISSING}b",
+			expanded: "ab",
+		},
+		"default_value": {
+			value:    "${MISSING:-default} ${EMPTY:-empty} ${HOST:-unused}",
+			expanded: "default empty Example.com",
+		},
+		"default_with_reference": {
+			value:    "${MISSING:-${HOST}:${MISSING:-80}}",
+			expanded: "Example.com:80",
+		},
+		"empty_accepted": {
+			value:    "${PORT:-default}",
+			options:  []Option{AcceptEmpty(true)},
+			expanded: "default",
+		},
+		"not_closed": {
+			value:      "a ${HOST",
+			errWrapped: ErrExpansionNotClosed,
+			errMessage: "variable reference is not closed: ${HOST",
+		},
+		"empty_key": {
+			value:      "${:-x}",
+			errWrapped: ErrExpansionKeyEmpty,
+			errMessage: "variable reference key is empty: ${:-x}",
+		},
+		"self_cycle": {
+			value:      "${KEY}",
+			errWrapped: ErrExpansionCycle,
+			errMessage: "variable references form a cycle: KEY -> KEY",
+		},
+		"cycle": {
+			value:      "${CYCLE_A}",
+			errWrapped: ErrExpansionCycle,
+			errMessage: "variable references form a cycle: " +
+				"KEY -> CYCLE_A -> CYCLE_B -> CYCLE_C -> CYCLE_A",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			settings := settingsFromOptions(testCase.options)
+
+			expanded, err := expand(sources, testCase.value, settings, []string{"KEY"})
+
+			if !errors.Is(err, testCase.errWrapped) {
+				t.Errorf("expected error %v to wrap %v", err, testCase.errWrapped)
+			}
+			if testCase.errWrapped != nil {
+				if err.Error() != testCase.errMessage {
+					t.Errorf("expected error message %q, got %q",
+						testCase.errMessage, err.Error())
+				}
+				return
+			}
+			if expanded != testCase.expanded {
+				t.Errorf("expected %q, got %q", testCase.expanded, expanded)
+			}
+		})
+	}
+}
+
+func Test_GetParse_expand(t *testing.T) {
+	t.Parallel()
+
+	sources := []Source{&testMapSource{keyToValue: map[string]string{
+		"PORT":       "${BASE}0",
+		"BASE":       "800",
+		"CYCLIC":     "${CYCLIC}",
+		"UNEXPANDED": "${BASE}",
+	}}}
+
+	port, err := Uint16(sources, "PORT", Expand(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if port != 8000 {
+		t.Errorf("expected port 8000, got %d", port)
+	}
+
+	_, err = Int(sources, "CYCLIC", Expand(true))
+	const expectedErrMessage = "map CYCLIC: expanding value: " +
+		"variable references form a cycle: CYCLIC -> CYCLIC"
+	if err == nil || err.Error() != expectedErrMessage {
+		t.Errorf("expected error %q, got %v", expectedErrMessage, err)
+	}
+
+	value, err := String(sources, "UNEXPANDED")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "${base}" {
+		t.Errorf("expected value %q, got %q", "${base}", value)
+	}
+}
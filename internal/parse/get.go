@@ -1,6 +1,7 @@
 package parse
 
 import (
+	"fmt"
 	"slices"
 	"strings"
 )
@@ -21,14 +22,71 @@ import (
 //   - Trim spaces.
 //   - Trim quotes.
 //   - Force lowercase.
-func Get(sources []Source, key string, options ...Option) (value *string) {
-	value, _ = get(sources, key, options...)
-	return value
+//
+// An error is returned with the source and key in its message if
+// a source fails to get the value, or if the Expand option is
+// enabled and the value cannot be expanded, in which case the
+// value is returned without expansion.
+func Get(sources []Source, key string, options ...Option) (
+	value *string, err error) {
+	value, origin, err := get(sources, key, options...)
+	if err != nil {
+		return value, origin.wrapError(key, err)
+	}
+	return value, nil
 }
 
+// origin describes where a value was found.
+type origin struct {
+	// sourceKind is the kind of the source, as returned
+	// by its String method.
+	sourceKind string
+	// location is the location of the key in the source,
+	// if the source implements the Locator interface.
+	// It is empty if the location is not known.
+	location string
+	// key is the source transformed key the value was found at.
+	key string
+	// deprecated is true if the key the value was found at
+	// is a deprecated key.
+	deprecated bool
+	// rawValue is the value as found in the source,
+	// before any processing.
+	rawValue string
+}
+
+// wrapError wraps the error given with the source kind,
+// the key and the eventual location of the key.
+func (o origin) wrapError(key string, err error) error {
+	if o.location == "" {
+		return fmt.Errorf("%s %s: %w", o.sourceKind, key, err)
+	}
+	return fmt.Errorf("%s %s (%s): %w", o.sourceKind, key, o.location, err)
+}
+
+// get returns the first value found at the given key from the given
+// sources in order, together with its origin. An error is returned
+// if a source implementing ErrorGetter fails to get the value, in
+// which case the value is nil, or if the Expand option is enabled
+// and the value cannot be expanded, in which case the value is
+// returned without expansion.
 func get(sources []Source, key string, options ...Option) (
-	value *string, sourceKind string) {
+	value *string, origin origin, err error) {
+	value, origin, _, err = getWithSource(sources, key, options...)
+	return value, origin, err
+}
+
+// getWithSource is like get but also returns the source the
+// value was found in, which is nil if the value is not found.
+func getWithSource(sources []Source, key string, options ...Option) (
+	value *string, origin origin, source Source, err error) {
 	settings := settingsFromOptions(options)
+	genericKey := key
+	if settings.observe != nil {
+		defer func() {
+			settings.observe(genericKey, newLookupResult(value, origin))
+		}()
+	}
 
 	keysToTry := make([]string, 0, 1+len(settings.deprecatedKeys))
 	keysToTry = append(keysToTry, settings.deprecatedKeys...)
@@ -43,8 +101,14 @@ func get(sources []Source, key string, options ...Option) (
 	for _, keyToTry := range keysToTry {
 		for _, sourceToTry := range sources {
 			transformedKeyToTry := sourceToTry.KeyTransform(keyToTry)
-			stringValue, isSet := sourceToTry.Get(transformedKeyToTry)
-			if !isSet || (!*settings.acceptEmpty && stringValue == "") {
+			stringValue, isSet, err := getFromSource(sourceToTry, transformedKeyToTry)
+			if err != nil {
+				origin.sourceKind = sourceToTry.String()
+				origin.key = transformedKeyToTry
+				return nil, origin, sourceToTry, err
+			}
+			if !isSet || (!*settings.acceptEmpty && stringValue == "" &&
+				!hasNonEmptyOccurrence(sourceToTry, transformedKeyToTry, settings)) {
 				continue
 			}
 			firstKeySet = transformedKeyToTry
@@ -59,20 +123,77 @@ func get(sources []Source, key string, options ...Option) (
 	}
 
 	if firstKeySet == "" { // All keys are unset for all sources
-		return nil, ""
+		return nil, origin, nil, nil
 	}
 
 	key = firstSource.KeyTransform(key)
-	sourceKind = firstSource.String()
+	origin.sourceKind = firstSource.String()
+	origin.key = firstKeySet
+	origin.rawValue = *value
+	locator, ok := firstSource.(Locator)
+	if ok {
+		origin.location = locator.Locate(firstKeySet)
+	}
 	if settings.currentKey != "" { // all keys are retro-compatible keys
 		currentKey := firstSource.KeyTransform(settings.currentKey)
-		settings.handleDeprecatedKey(sourceKind, firstKeySet, currentKey)
+		settings.handleDeprecatedKey(origin.sourceKind, firstKeySet, currentKey)
+		origin.deprecated = true
 	} else if firstKeySet != key {
-		settings.handleDeprecatedKey(sourceKind, firstKeySet, key)
+		settings.handleDeprecatedKey(origin.sourceKind, firstKeySet, key)
+		origin.deprecated = true
 	}
 
-	*value = postProcessValue(*value, settings)
-	return value, sourceKind
+	*value, err = processValue(sources, *value, settings, genericKey)
+	return value, origin, firstSource, err
+}
+
+// hasNonEmptyOccurrence returns true if the multiValues settings
+// field is set and the source implements the MultiGetter interface
+// and has at least one non-empty value for the transformed key given.
+func hasNonEmptyOccurrence(source Source, key string, settings settings) bool {
+	if !settings.multiValues {
+		return false
+	}
+	multiGetter, ok := source.(MultiGetter)
+	if !ok {
+		return false
+	}
+	for _, value := range multiGetter.GetAll(key) {
+		if value != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// processValue expands the value given if the Expand option is
+// enabled, and then post-processes it. If the value cannot be
+// expanded, it is post-processed without expansion and an error
+// is returned. The key is the generic key the value is found at,
+// used to detect reference cycles.
+func processValue(sources []Source, value string, settings settings,
+	key string) (processed string, err error) {
+	if *settings.expand {
+		expanded, err := expand(sources, value, settings, []string{key})
+		if err != nil {
+			return postProcessValue(value, settings), fmt.Errorf("expanding value: %w", err)
+		}
+		value = expanded
+	}
+	return postProcessValue(value, settings), nil
+}
+
+// getFromSource returns the value of the transformed key given
+// from the source, using its GetWithError method if it implements
+// the ErrorGetter interface.
+func getFromSource(source Source, key string) (
+	value string, isSet bool, err error) {
+	errorGetter, ok := source.(ErrorGetter)
+	if !ok {
+		value, isSet = source.Get(key)
+		return value, isSet, nil
+	}
+	return errorGetter.GetWithError(key)
 }
 
 func postProcessValue(value string, settings settings) string {
@@ -1,6 +1,7 @@
 package parse
 
 import (
+	"errors"
 	"testing"
 
 	gomock "github.com/golang/mock/gomock"
@@ -14,7 +15,7 @@ func Test_get(t *testing.T) {
 		key         string
 		options     []Option
 		value       *string
-		sourceKind  string
+		origin      origin
 	}{
 		"no_source": {
 			key: "KEY",
@@ -37,9 +38,9 @@ func Test_get(t *testing.T) {
 				source.EXPECT().String().Return("A")
 				return []Source{source}
 			},
-			key:        "KEY",
-			value:      ptrTo("value"),
-			sourceKind: "A",
+			key:    "KEY",
+			value:  ptrTo("value"),
+			origin: origin{sourceKind: "A", key: "key_transformed", rawValue: "value"},
 		},
 		"found_in_2_of_3_sources": {
 			makeSources: func(ctrl *gomock.Controller) []Source {
@@ -53,9 +54,32 @@ func Test_get(t *testing.T) {
 				sourceC := NewMockSource(ctrl)
 				return []Source{sourceA, sourceB, sourceC}
 			},
-			key:        "KEY",
-			value:      ptrTo("value"),
-			sourceKind: "B",
+			key:    "KEY",
+			value:  ptrTo("value"),
+			origin: origin{sourceKind: "B", key: "key_transformed", rawValue: "value"},
+		},
+		"found_in_locator_source": {
+			makeSources: func(ctrl *gomock.Controller) []Source {
+				source := NewMockSource(ctrl)
+				source.EXPECT().KeyTransform("KEY").Return("key_transformed").Times(2)
+				source.EXPECT().Get("key_transformed").Return("value", true)
+				source.EXPECT().String().Return("A")
+				locator := &testLocatorSource{
+					Source: source,
+					keyToLocation: map[string]string{
+						"key_transformed": "line 1",
+					},
+				}
+				return []Source{locator}
+			},
+			key:   "KEY",
+			value: ptrTo("value"),
+			origin: origin{
+				sourceKind: "A",
+				location:   "line 1",
+				key:        "key_transformed",
+				rawValue:   "value",
+			},
 		},
 		"found_current_key_with_retro_keys": {
 			makeSources: func(ctrl *gomock.Controller) []Source {
@@ -74,8 +98,8 @@ func Test_get(t *testing.T) {
 				RetroKeys(func(source string, deprecateKey string, currentKey string) {},
 					"OLDEST_DEPRECATED_KEY", "NEWEST_DEPRECATED_KEY"),
 			},
-			value:      ptrTo("value"),
-			sourceKind: "A",
+			value:  ptrTo("value"),
+			origin: origin{sourceKind: "A", key: "key_transformed", rawValue: "value"},
 		},
 		"found_after_empty_set_in_first_retrokey": {
 			makeSources: func(ctrl *gomock.Controller) []Source {
@@ -92,8 +116,30 @@ func Test_get(t *testing.T) {
 				RetroKeys(func(source string, deprecateKey string, currentKey string) {},
 					"DEPRECATED_KEY"),
 			},
-			value:      ptrTo("value"),
-			sourceKind: "A",
+			value:  ptrTo("value"),
+			origin: origin{sourceKind: "A", key: "key_transformed", rawValue: "value"},
+		},
+		"found_deprecated_key": {
+			makeSources: func(ctrl *gomock.Controller) []Source {
+				source := NewMockSource(ctrl)
+				source.EXPECT().KeyTransform("DEPRECATED_KEY").Return("deprecated_key_transformed")
+				source.EXPECT().Get("deprecated_key_transformed").Return(" Value\n", true)
+				source.EXPECT().KeyTransform("KEY").Return("key_transformed")
+				source.EXPECT().String().Return("A")
+				return []Source{source}
+			},
+			key: "KEY",
+			options: []Option{
+				RetroKeys(func(source string, deprecateKey string, currentKey string) {},
+					"DEPRECATED_KEY"),
+			},
+			value: ptrTo("value"),
+			origin: origin{
+				sourceKind: "A",
+				key:        "deprecated_key_transformed",
+				deprecated: true,
+				rawValue:   " Value\n",
+			},
 		},
 	}
 
@@ -108,14 +154,63 @@ func Test_get(t *testing.T) {
 				sources = testCase.makeSources(ctrl)
 			}
 
-			value, sourceKind := get(sources, testCase.key, testCase.options...)
+			value, origin, err := get(sources, testCase.key, testCase.options...)
+			if err != nil {
+				t.Fatal(err)
+			}
 			if (value == nil && testCase.value != nil) ||
 				(value != nil && testCase.value == nil) ||
 				(value != nil && *value != *testCase.value) {
 				t.Errorf("expected %v, got %v", testCase.value, value)
 			}
-			if sourceKind != testCase.sourceKind {
-				t.Errorf("expected %s, got %s", testCase.sourceKind, sourceKind)
+			if origin != testCase.origin {
+				t.Errorf("expected %#v, got %#v", testCase.origin, origin)
+			}
+		})
+	}
+}
+
+type testLocatorSource struct {
+	Source
+	keyToLocation map[string]string
+}
+
+func (t *testLocatorSource) Locate(key string) (location string) {
+	return t.keyToLocation[key]
+}
+
+func Test_origin_wrapError(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+
+	testCases := map[string]struct {
+		origin     origin
+		errMessage string
+	}{
+		"without_location": {
+			origin:     origin{sourceKind: "environment variable"},
+			errMessage: "environment variable KEY: test error",
+		},
+		"with_location": {
+			origin:     origin{sourceKind: "TOML file config.toml", location: "line 3"},
+			errMessage: "TOML file config.toml KEY (line 3): test error",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := testCase.origin.wrapError("KEY", errTest)
+
+			if !errors.Is(err, errTest) {
+				t.Errorf("expected error %v to wrap %v", err, errTest)
+			}
+			if err.Error() != testCase.errMessage {
+				t.Errorf("expected error message %q, got %q",
+					testCase.errMessage, err.Error())
 			}
 		})
 	}
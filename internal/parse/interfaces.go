@@ -11,3 +11,32 @@ type Source interface {
 	// the flags source.
 	KeyTransform(key string) string
 }
+
+// Locator is an optional interface a Source can implement
+// to give the location of a key in the source, such as a
+// line number in a file, to be used in error messages.
+type Locator interface {
+	// Locate returns the location of the given transformed key,
+	// for example `line 5`, or the empty string if unknown.
+	Locate(key string) (location string)
+}
+
+// MultiGetter is an optional interface a Source can implement
+// to return all the values set for a key, such as for a flag
+// given more than once. Comma separated values parsing functions
+// then concatenate the values of all the occurrences of the key.
+type MultiGetter interface {
+	// GetAll returns all the values set for the given
+	// transformed key, in order, or nil if it is not set.
+	GetAll(key string) (values []string)
+}
+
+// ErrorGetter is an optional interface a Source can implement
+// to report an error getting the value of a key, such as a
+// value file which cannot be read. It is then used instead of
+// the Get method, and its error is returned by parsing functions.
+type ErrorGetter interface {
+	// GetWithError returns the value of the given transformed
+	// key, whether it is set, and an error if it cannot be read.
+	GetWithError(key string) (value string, isSet bool, err error)
+}
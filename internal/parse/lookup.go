@@ -0,0 +1,57 @@
+package parse
+
+// LookupResult contains the value found at a key
+// together with its provenance.
+type LookupResult struct {
+	// IsSet is true if the key is set in one of the sources.
+	// All other fields are left empty if it is false.
+	IsSet bool
+	// RawValue is the value as found in the source,
+	// before any processing.
+	RawValue string
+	// Value is the value after processing, as it would
+	// be returned by the String function.
+	Value string
+	// Source is the name of the source the value was
+	// found in, as returned by its String method.
+	Source string
+	// Key is the source transformed key the value was found at.
+	Key string
+	// Location is the location of the key in the source, if
+	// the source implements the Locator interface, and is
+	// empty otherwise.
+	Location string
+	// Deprecated is true if the value was found at a
+	// deprecated key instead of the current key.
+	Deprecated bool
+}
+
+// Lookup returns the first value found at the given key from
+// the given sources in order, together with its provenance.
+// The key is considered as not set in the same cases as the
+// Get function, and the value is processed in the same way.
+// An error is returned in the same cases as the Get function.
+func Lookup(sources []Source, key string, options ...Option) (
+	result LookupResult, err error) {
+	value, origin, err := get(sources, key, options...)
+	if err != nil {
+		err = origin.wrapError(key, err)
+	}
+	return newLookupResult(value, origin), err
+}
+
+func newLookupResult(value *string, origin origin) (result LookupResult) {
+	if value == nil {
+		return result
+	}
+
+	return LookupResult{
+		IsSet:      true,
+		RawValue:   origin.rawValue,
+		Value:      *value,
+		Source:     origin.sourceKind,
+		Key:        origin.key,
+		Location:   origin.location,
+		Deprecated: origin.deprecated,
+	}
+}
@@ -0,0 +1,77 @@
+package parse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_Lookup(t *testing.T) {
+	t.Parallel()
+
+	sources := []Source{&testMapSource{keyToValue: map[string]string{
+		"OLD_KEY": " 'Value'\n",
+		"KEY":     "other",
+	}}}
+
+	result, err := Lookup(sources, "KEY", RetroKeys(nil, "OLD_KEY"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := LookupResult{
+		IsSet:      true,
+		RawValue:   " 'Value'\n",
+		Value:      "value",
+		Source:     "map",
+		Key:        "OLD_KEY",
+		Deprecated: true,
+	}
+	if result != expected {
+		t.Errorf("expected %#v, got %#v", expected, result)
+	}
+
+	result, err = Lookup(sources, "MISSING")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != (LookupResult{}) {
+		t.Errorf("expected empty result, got %#v", result)
+	}
+}
+
+func Test_Observe(t *testing.T) {
+	t.Parallel()
+
+	sources := []Source{&testMapSource{keyToValue: map[string]string{
+		"PORT": "8000",
+	}}}
+
+	type observation struct {
+		key    string
+		result LookupResult
+	}
+	var observations []observation
+	observe := Observe(func(key string, result LookupResult) {
+		observations = append(observations, observation{key: key, result: result})
+	})
+
+	_, err := Uint16(sources, "PORT", observe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = String(sources, "MISSING", observe)
+
+	expected := []observation{
+		{key: "PORT", result: LookupResult{
+			IsSet:    true,
+			RawValue: "8000",
+			Value:    "8000",
+			Source:   "map",
+			Key:      "PORT",
+		}},
+		{key: "MISSING"},
+	}
+	if !reflect.DeepEqual(observations, expected) {
+		t.Errorf("expected %#v, got %#v", expected, observations)
+	}
+}
@@ -23,6 +23,17 @@ func AcceptEmpty(accept bool) Option {
 	}
 }
 
+// Expand, if set to true, expands `${KEY}` and `${KEY:-default}`
+// references in values read, by resolving the referenced keys
+// through the sources given in order. The default value is used
+// if the referenced key is unset or empty, and `$$` is replaced
+// by a literal `$`. By default, values are not expanded.
+func Expand(expand bool) Option {
+	return func(s *settings) {
+		s.expand = &expand
+	}
+}
+
 // RetroKeys specifies a list of keys that are deprecated and
 // replaced by the current key.
 // The oldest deprecated key should be placed first
@@ -49,3 +60,20 @@ func IsRetro(handleDeprecatedKey func(source, deprecateKey, currentKey string),
 		s.handleDeprecatedKey = handleDeprecatedKey
 	}
 }
+
+// multiValues is the option used by the comma separated values
+// functions to set the multiValues settings field.
+func multiValues() Option {
+	return func(s *settings) {
+		s.multiValues = true
+	}
+}
+
+// Observe sets a function called with the key and the lookup
+// result each time a value is looked up for a key, whether the
+// key is set or not.
+func Observe(observe func(key string, result LookupResult)) Option {
+	return func(s *settings) {
+		s.observe = observe
+	}
+}
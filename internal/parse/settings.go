@@ -10,9 +10,15 @@ type settings struct {
 	trimQuotes          *bool
 	forceLowercase      *bool
 	acceptEmpty         *bool
+	expand              *bool
 	currentKey          string
 	deprecatedKeys      []string
 	handleDeprecatedKey func(source, deprecateKey, currentKey string)
+	observe             func(key string, result LookupResult)
+	// multiValues is set by the comma separated values functions,
+	// such that a source implementing MultiGetter is considered as
+	// set if one of the occurrences of the key is not empty.
+	multiValues bool
 }
 
 func settingsFromOptions(options []Option) (s settings) {
@@ -29,6 +35,7 @@ func (s *settings) setDefaults() {
 	s.trimQuotes = gosettings.DefaultPointer(s.trimQuotes, true)
 	s.forceLowercase = gosettings.DefaultPointer(s.forceLowercase, true)
 	s.acceptEmpty = gosettings.DefaultPointer(s.acceptEmpty, false)
+	s.expand = gosettings.DefaultPointer(s.expand, false)
 	if s.handleDeprecatedKey == nil {
 		s.handleDeprecatedKey = func(source, deprecateKey, currentKey string) {}
 	}
@@ -1,6 +1,7 @@
 package parse
 
 import (
+	"strings"
 	"time"
 )
 
@@ -30,3 +31,48 @@ func Duration(sources []Source, key string,
 	options ...Option) (duration time.Duration, err error) {
 	return GetParse(sources, key, time.ParseDuration, options...)
 }
+
+// TimePtr returns a pointer to a `time.Time` parsed from the first
+// value found at the given key from the given sources in order.
+// The value can be a RFC 3339 date-time, or a date-time or date
+// without time offset, which is then interpreted as UTC.
+// If the value is not a valid time string, an error is returned
+// with the source name and key in its message.
+// The value is returned as `nil` if:
+//   - the key given is NOT set in any of the sources.
+//   - By default and unless changed by the AllowEmpty option, if the
+//     key is set and its corresponding value is empty.
+func TimePtr(sources []Source, key string,
+	options ...Option) (timePtr *time.Time, err error) {
+	return GetParsePtr(sources, key, parseTime, options...)
+}
+
+// Time returns a `time.Time` parsed from the first value found
+// at the given key from the given sources in order.
+// The value can be a RFC 3339 date-time, or a date-time or date
+// without time offset, which is then interpreted as UTC.
+// If the value is not a valid time string, an error is returned
+// with the source name and key in its message.
+// The value is returned as the zero `time.Time{}` if:
+//   - the key given is NOT set in any of the sources.
+//   - By default and unless changed by the AllowEmpty option, if the
+//     key is set and its corresponding value is empty.
+func Time(sources []Source, key string,
+	options ...Option) (t time.Time, err error) {
+	return GetParse(sources, key, parseTime, options...)
+}
+
+func parseTime(value string) (t time.Time, err error) {
+	// RFC 3339 allows lowercase `t` and `z`, which are notably
+	// produced by the default ForceLowercase option.
+	value = strings.ToUpper(value)
+	layouts := []string{time.RFC3339Nano, "2006-01-02T15:04:05", time.DateOnly}
+	for _, layout := range layouts {
+		t, err = time.Parse(layout, value)
+		if err == nil {
+			return t, nil
+		}
+	}
+	// Return the error for the RFC 3339 layout
+	return time.Parse(time.RFC3339Nano, value)
+}
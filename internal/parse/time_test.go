@@ -0,0 +1,60 @@
+package parse
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_parseTime(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		value      string
+		t          time.Time
+		errMessage string
+	}{
+		"rfc3339": {
+			value: "1979-05-27T07:32:00.5-07:00",
+			t: time.Date(1979, 5, 27, 7, 32, 0, 500000000,
+				time.FixedZone("", -7*60*60)),
+		},
+		"rfc3339_lowercased": {
+			value: "1979-05-27t07:32:00z",
+			t:     time.Date(1979, 5, 27, 7, 32, 0, 0, time.UTC),
+		},
+		"local_date_time": {
+			value: "1979-05-27T07:32:00",
+			t:     time.Date(1979, 5, 27, 7, 32, 0, 0, time.UTC),
+		},
+		"local_date": {
+			value: "1979-05-27",
+			t:     time.Date(1979, 5, 27, 0, 0, 0, 0, time.UTC),
+		},
+		"invalid": {
+			value: "27/05/1979",
+			errMessage: `parsing time "27/05/1979" as "2006-01-02T15:04:05.999999999Z07:00": ` +
+				`cannot parse "27/05/1979" as "2006"`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			parsed, err := parseTime(testCase.value)
+
+			if testCase.errMessage != "" {
+				if err == nil || err.Error() != testCase.errMessage {
+					t.Errorf("expected error message %q, got %v", testCase.errMessage, err)
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if !parsed.Equal(testCase.t) {
+				t.Errorf("expected %s, got %s", testCase.t, parsed)
+			}
+		})
+	}
+}
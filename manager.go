@@ -0,0 +1,117 @@
+package gosettings
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Manager holds settings of type T which can be read and updated
+// concurrently at runtime, and notifies subscribers of updates.
+// It must be created with NewManager.
+type Manager[T any] struct {
+	// updateMutex serializes updates and their notifications,
+	// so subscribers receive updates in order.
+	updateMutex sync.Mutex
+	// mutex protects the current settings.
+	mutex    sync.RWMutex
+	current  T
+	copy     func(settings T) T
+	override func(settings *T, other T)
+	validate func(settings T) error
+
+	subscribersMutex sync.Mutex
+	subscribers      map[uint]func(oldSettings, newSettings T)
+	nextSubscriberID uint
+}
+
+// NewManager creates a new settings manager with a copy of the
+// initial settings given, which must be valid. The settings type
+// T must have, on its pointer receiver, the methods Copy, OverrideWith
+// and Validate, for example `func (s *Settings) Copy() Settings`.
+// Initial settings should typically have their defaults set already.
+func NewManager[T any, P interface {
+	*T
+	Copier[T]
+	OverrideWither[T]
+	Validate() error
+}](initial T) (manager *Manager[T], err error) {
+	err = P(&initial).Validate()
+	if err != nil {
+		return nil, fmt.Errorf("validating initial settings: %w", err)
+	}
+
+	return &Manager[T]{
+		current:     P(&initial).Copy(),
+		copy:        func(settings T) T { return P(&settings).Copy() },
+		override:    func(settings *T, other T) { P(settings).OverrideWith(other) },
+		validate:    func(settings T) error { return P(&settings).Validate() },
+		subscribers: make(map[uint]func(oldSettings, newSettings T)),
+	}, nil
+}
+
+// Get returns a copy of the current settings.
+func (m *Manager[T]) Get() (settings T) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.copy(m.current)
+}
+
+// Update overrides a copy of the current settings with the set fields
+// of the partial settings given, validates the resulting settings and
+// only then swaps the current settings with them. Subscribers are then
+// notified with the old and new settings, before Update returns.
+// Subscribers are notified on every successful update, even if the
+// settings values did not change. An error is returned if the
+// resulting settings are not valid, in which case the current
+// settings are left unchanged and subscribers are not notified.
+// Note Update must not be called from a subscriber callback.
+func (m *Manager[T]) Update(partial T) (err error) {
+	m.updateMutex.Lock()
+	defer m.updateMutex.Unlock()
+
+	m.mutex.RLock()
+	oldSettings := m.current
+	m.mutex.RUnlock()
+
+	newSettings := m.copy(oldSettings)
+	m.override(&newSettings, partial)
+	err = m.validate(newSettings)
+	if err != nil {
+		return fmt.Errorf("validating updated settings: %w", err)
+	}
+
+	m.mutex.Lock()
+	m.current = newSettings
+	m.mutex.Unlock()
+
+	m.subscribersMutex.Lock()
+	callbacks := make([]func(oldSettings, newSettings T), 0, len(m.subscribers))
+	for _, callback := range m.subscribers {
+		callbacks = append(callbacks, callback)
+	}
+	m.subscribersMutex.Unlock()
+
+	for _, callback := range callbacks {
+		callback(m.copy(oldSettings), m.copy(newSettings))
+	}
+	return nil
+}
+
+// Subscribe registers the callback given to be called with copies
+// of the old and new settings after each successful update.
+// Callbacks are called synchronously in Update, in no particular
+// order, and should return quickly. The unsubscribe function returned
+// removes the callback, and is safe to call more than once.
+func (m *Manager[T]) Subscribe(callback func(oldSettings, newSettings T)) (
+	unsubscribe func()) {
+	m.subscribersMutex.Lock()
+	defer m.subscribersMutex.Unlock()
+	id := m.nextSubscriberID
+	m.nextSubscriberID++
+	m.subscribers[id] = callback
+	return func() {
+		m.subscribersMutex.Lock()
+		defer m.subscribersMutex.Unlock()
+		delete(m.subscribers, id)
+	}
+}
@@ -0,0 +1,153 @@
+package gosettings
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func Test_NewManager(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid_initial_settings", func(t *testing.T) {
+		t.Parallel()
+
+		manager, err := NewManager(testNested{Name: "invalid"})
+
+		if manager != nil {
+			t.Error("expected nil manager")
+		}
+		if !errors.Is(err, errTestNameNotValid) {
+			t.Errorf("expected error %v to wrap %v", err, errTestNameNotValid)
+		}
+		const expectedMessage = "validating initial settings: name is not valid"
+		if err.Error() != expectedMessage {
+			t.Errorf("expected error message %q but got %q", expectedMessage, err.Error())
+		}
+	})
+
+	t.Run("initial_settings_copied", func(t *testing.T) {
+		t.Parallel()
+
+		initial := testNested{Name: "a", Values: []int{1}}
+
+		manager, err := NewManager(initial)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		initial.Values[0] = 9
+		expected := testNested{Name: "a", Values: []int{1}}
+		if settings := manager.Get(); !reflect.DeepEqual(settings, expected) {
+			t.Errorf("expected %v, got %v", expected, settings)
+		}
+	})
+}
+
+func Test_Manager_Get(t *testing.T) {
+	t.Parallel()
+
+	manager, err := NewManager(testNested{Values: []int{1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	settings := manager.Get()
+	settings.Values[0] = 9
+
+	expected := testNested{Values: []int{1}}
+	if settings := manager.Get(); !reflect.DeepEqual(settings, expected) {
+		t.Errorf("expected %v, got %v", expected, settings)
+	}
+}
+
+func Test_Manager_Update(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid_update", func(t *testing.T) {
+		t.Parallel()
+
+		manager, err := NewManager(testNested{Name: "a"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		manager.Subscribe(func(_, _ testNested) {
+			t.Error("subscriber must not be called")
+		})
+
+		err = manager.Update(testNested{Name: "invalid"})
+
+		if !errors.Is(err, errTestNameNotValid) {
+			t.Errorf("expected error %v to wrap %v", err, errTestNameNotValid)
+		}
+		expected := testNested{Name: "a"}
+		if settings := manager.Get(); !reflect.DeepEqual(settings, expected) {
+			t.Errorf("expected %v, got %v", expected, settings)
+		}
+	})
+
+	t.Run("update_and_notify", func(t *testing.T) {
+		t.Parallel()
+
+		manager, err := NewManager(testNested{Name: "a", Values: []int{1}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var calls int
+		unsubscribe := manager.Subscribe(func(oldSettings, newSettings testNested) {
+			calls++
+			expectedOld := testNested{Name: "a", Values: []int{1}}
+			if !reflect.DeepEqual(oldSettings, expectedOld) {
+				t.Errorf("expected old settings %v, got %v", expectedOld, oldSettings)
+			}
+			expectedNew := testNested{Name: "b", Values: []int{1}}
+			if !reflect.DeepEqual(newSettings, expectedNew) {
+				t.Errorf("expected new settings %v, got %v", expectedNew, newSettings)
+			}
+			newSettings.Values[0] = 9 // must not affect the manager
+		})
+
+		err = manager.Update(testNested{Name: "b"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := testNested{Name: "b", Values: []int{1}}
+		if settings := manager.Get(); !reflect.DeepEqual(settings, expected) {
+			t.Errorf("expected %v, got %v", expected, settings)
+		}
+
+		unsubscribe()
+		unsubscribe()
+		err = manager.Update(testNested{Name: "c"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if calls != 1 {
+			t.Errorf("expected 1 subscriber call, got %d", calls)
+		}
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		t.Parallel()
+
+		manager, err := NewManager(testNested{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		const workers = 10
+		var waitGroup sync.WaitGroup
+		waitGroup.Add(workers)
+		for i := 0; i < workers; i++ {
+			go func() {
+				defer waitGroup.Done()
+				_ = manager.Update(testNested{Values: []int{1}})
+				_ = manager.Get()
+			}()
+		}
+		waitGroup.Wait()
+	})
+}
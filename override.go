@@ -45,6 +45,68 @@ func OverrideWithSlice[T any](existing, other []T) (result []T) {
 	return result
 }
 
+// OverrideWithMap returns the existing map argument if the other
+// map argument is nil. Otherwise it returns a new map with the
+// copied keys and values of the other map argument, replacing
+// the existing map entirely. Note an empty non-nil other map
+// overrides the existing map to an empty map.
+// To override each key individually, use MergeMap.
+func OverrideWithMap[K comparable, V any](existing, other map[K]V) (
+	result map[K]V) {
+	if other == nil {
+		return existing
+	}
+	result = make(map[K]V, len(other))
+	for key, value := range other {
+		result[key] = value
+	}
+	return result
+}
+
+// MergeMap returns the existing map argument if the other map
+// argument is nil. Otherwise it returns a new map with the copied
+// keys and values of the existing map, where each key of the other
+// map overrides the existing value for this key.
+// Keys only present in the existing map are kept.
+func MergeMap[K comparable, V any](existing, other map[K]V) (
+	result map[K]V) {
+	if other == nil {
+		return existing
+	}
+	result = make(map[K]V, len(existing)+len(other))
+	for key, value := range existing {
+		result[key] = value
+	}
+	for key, value := range other {
+		result[key] = value
+	}
+	return result
+}
+
+// OverrideWithNested returns the existing argument if the other
+// argument is nil. Otherwise it returns a new pointer to a deep copy
+// of the existing value, overridden with the other value using its
+// OverrideWith method. If the existing argument is nil, it returns
+// a new pointer to a deep copy of the other value.
+// Neither the existing nor the other values are modified.
+func OverrideWithNested[T any, P interface {
+	*T
+	Copier[T]
+	OverrideWither[T]
+}](existing, other P) (result P) {
+	switch {
+	case other == nil:
+		return existing
+	case existing == nil:
+		value := other.Copy()
+		return &value
+	}
+	value := existing.Copy()
+	result = &value
+	result.OverrideWith(other.Copy())
+	return result
+}
+
 // OverrideWithValidator returns the existing argument if other is not valid,
 // otherwise it returns the other argument.
 func OverrideWithValidator[T SelfValidator](existing, other T) ( //nolint:ireturn
@@ -1,6 +1,9 @@
 package gosettings
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func Test_OverrideWithComparable(t *testing.T) {
 	t.Parallel()
@@ -48,3 +51,139 @@ func Test_OverrideWithComparable(t *testing.T) {
 		})
 	}
 }
+
+func Test_OverrideWithMap(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		existing map[string]int
+		other    map[string]int
+		result   map[string]int
+	}{
+		"all_nil": {},
+		"nil_other_keeps_existing": {
+			existing: map[string]int{"a": 1},
+			result:   map[string]int{"a": 1},
+		},
+		"empty_other_replaces_existing": {
+			existing: map[string]int{"a": 1},
+			other:    map[string]int{},
+			result:   map[string]int{},
+		},
+		"other_replaces_existing": {
+			existing: map[string]int{"a": 1, "b": 2},
+			other:    map[string]int{"b": 3},
+			result:   map[string]int{"b": 3},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result := OverrideWithMap(testCase.existing, testCase.other)
+
+			if !reflect.DeepEqual(result, testCase.result) {
+				t.Fatalf("expected %v, got %v", testCase.result, result)
+			}
+		})
+	}
+}
+
+func Test_MergeMap(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		existing map[string]int
+		other    map[string]int
+		result   map[string]int
+	}{
+		"all_nil": {},
+		"nil_other_keeps_existing": {
+			existing: map[string]int{"a": 1},
+			result:   map[string]int{"a": 1},
+		},
+		"empty_other_keeps_existing_values": {
+			existing: map[string]int{"a": 1},
+			other:    map[string]int{},
+			result:   map[string]int{"a": 1},
+		},
+		"nil_existing": {
+			other:  map[string]int{"a": 1},
+			result: map[string]int{"a": 1},
+		},
+		"merge": {
+			existing: map[string]int{"a": 1, "b": 2},
+			other:    map[string]int{"b": 3, "c": 4},
+			result:   map[string]int{"a": 1, "b": 3, "c": 4},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			existingLength := len(testCase.existing)
+
+			result := MergeMap(testCase.existing, testCase.other)
+
+			if !reflect.DeepEqual(result, testCase.result) {
+				t.Fatalf("expected %v, got %v", testCase.result, result)
+			}
+			if len(testCase.existing) != existingLength {
+				t.Error("existing map was mutated")
+			}
+		})
+	}
+}
+
+func Test_OverrideWithNested(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		existing *testNested
+		other    *testNested
+		result   *testNested
+	}{
+		"all_nil": {},
+		"nil_other_keeps_existing": {
+			existing: &testNested{Name: "a"},
+			result:   &testNested{Name: "a"},
+		},
+		"nil_existing": {
+			other:  &testNested{Name: "b", Values: []int{1}},
+			result: &testNested{Name: "b", Values: []int{1}},
+		},
+		"override_set_fields": {
+			existing: &testNested{Name: "a", Values: []int{1}},
+			other:    &testNested{Values: []int{2}},
+			result:   &testNested{Name: "a", Values: []int{2}},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var existingCopy *testNested
+			if testCase.existing != nil {
+				existingCopy = CopyNested(testCase.existing)
+			}
+
+			result := OverrideWithNested(testCase.existing, testCase.other)
+
+			if !reflect.DeepEqual(result, testCase.result) {
+				t.Fatalf("expected %v, got %v", testCase.result, result)
+			}
+			if !reflect.DeepEqual(testCase.existing, existingCopy) {
+				t.Error("existing value was mutated")
+			}
+			if testCase.other != nil && result == testCase.other {
+				t.Error("result must not be the other pointer")
+			}
+		})
+	}
+}
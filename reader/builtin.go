@@ -19,7 +19,22 @@ import (
 //   - Trim spaces.
 //   - Trim quotes.
 //   - Force lowercase.
+//
+// Errors are ignored, and GetWithError should be used instead
+// to get them.
 func (r *Reader) Get(key string, options ...Option) (value *string) {
+	value, _ = r.GetWithError(key, options...)
+	return value
+}
+
+// GetWithError returns a value found at the given key as a string
+// pointer, in the same way as the Get method.
+// An error is returned with the source and key in its message if
+// a source fails to get the value, such as a file suffixed environment
+// variable, or if the Expand option is enabled and the value cannot
+// be expanded, such as with a reference cycle, in which case the
+// value is returned without expansion.
+func (r *Reader) GetWithError(key string, options ...Option) (value *string, err error) {
 	parseOptions := r.makeParseOptions(options)
 	return parse.Get(r.sources, key, parseOptions...)
 }
@@ -33,7 +48,17 @@ func (r *Reader) Get(key string, options ...Option) (value *string) {
 //   - Force lowercase.
 //
 // If the key is not set, the empty string is returned.
+// Errors are ignored, and StringWithError should be used
+// instead to get them.
 func (r *Reader) String(key string, options ...Option) (value string) {
+	value, _ = r.StringWithError(key, options...)
+	return value
+}
+
+// StringWithError returns a string from the value found at the
+// given key, in the same way as the String method.
+// An error is returned in the same cases as the GetWithError method.
+func (r *Reader) StringWithError(key string, options ...Option) (value string, err error) {
 	parseOptions := r.makeParseOptions(options)
 	return parse.String(r.sources, key, parseOptions...)
 }
@@ -48,11 +73,27 @@ func (r *Reader) String(key string, options ...Option) (value string) {
 //   - Trim quotes.
 //   - Force lowercase.
 //
+// If the source the key is found in implements the MultiGetter
+// interface, such as the flag source, the comma separated values
+// of all the occurrences of the key are concatenated, such that
+// `--header a,b --header c` gives [a b c].
+//
 // The slice is returned as `nil` if:
 //   - the given key is NOT set.
 //   - By default and unless changed by the AcceptEmpty option,
 //     if the key is set and its corresponding value is empty.
+//
+// Errors are ignored, and CSVWithError should be used instead
+// to get them.
 func (r *Reader) CSV(key string, options ...Option) (values []string) {
+	values, _ = r.CSVWithError(key, options...)
+	return values
+}
+
+// CSVWithError returns a slice of strings from a comma separated
+// value found at the given key, in the same way as the CSV method.
+// An error is returned in the same cases as the GetWithError method.
+func (r *Reader) CSVWithError(key string, options ...Option) (values []string, err error) {
 	parseOptions := r.makeParseOptions(options)
 	return parse.CSV(r.sources, key, parseOptions...)
 }
@@ -0,0 +1,431 @@
+package reader
+
+import (
+	"errors"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+// Collector wraps a Reader to collect the errors of its typed
+// methods instead of returning them, so that all invalid values
+// can be reported at once. Each method has the same behavior as
+// its Reader counterpart, except its error is recorded instead
+// of being returned.
+// All the errors collected can be retrieved with the Err method.
+type Collector struct {
+	reader *Reader
+	mutex  sync.Mutex
+	errs   []error
+}
+
+// NewCollector creates a new collector wrapping the reader given.
+func NewCollector(reader *Reader) *Collector {
+	return &Collector{
+		reader: reader,
+	}
+}
+
+// Err returns all the errors collected joined together using
+// errors.Join, such that each error message is on its own line
+// and errors.Is can be used to check for wrapped errors such as
+// ErrValueNotInRange. It returns nil if no error was collected.
+func (c *Collector) Err() (err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return errors.Join(c.errs...)
+}
+
+func (c *Collector) collect(err error) {
+	if err == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.errs = append(c.errs, err)
+}
+
+// Get returns the value found at the given key as a string pointer,
+// as the Reader GetWithError method, collecting its eventual error.
+func (c *Collector) Get(key string, options ...Option) (value *string) {
+	value, err := c.reader.GetWithError(key, options...)
+	c.collect(err)
+	return value
+}
+
+// String returns the string value found at the given key,
+// as the Reader StringWithError method, collecting its eventual error.
+func (c *Collector) String(key string, options ...Option) (value string) {
+	value, err := c.reader.StringWithError(key, options...)
+	c.collect(err)
+	return value
+}
+
+// CSV returns a slice of strings from the comma separated value
+// found at the given key, as the Reader CSVWithError method, collecting
+// its eventual error.
+func (c *Collector) CSV(key string, options ...Option) (values []string) {
+	values, err := c.reader.CSVWithError(key, options...)
+	c.collect(err)
+	return values
+}
+
+// Int returns an `int` from the value found at the given key,
+// as the Reader Int method, collecting its eventual error.
+func (c *Collector) Int(key string, options ...Option) (n int) {
+	n, err := c.reader.Int(key, options...)
+	c.collect(err)
+	return n
+}
+
+// Int8 returns an `int8` from the value found at the given key,
+// as the Reader Int8 method, collecting its eventual error.
+func (c *Collector) Int8(key string, options ...Option) (n int8) {
+	n, err := c.reader.Int8(key, options...)
+	c.collect(err)
+	return n
+}
+
+// Int16 returns an `int16` from the value found at the given key,
+// as the Reader Int16 method, collecting its eventual error.
+func (c *Collector) Int16(key string, options ...Option) (n int16) {
+	n, err := c.reader.Int16(key, options...)
+	c.collect(err)
+	return n
+}
+
+// Int32 returns an `int32` from the value found at the given key,
+// as the Reader Int32 method, collecting its eventual error.
+func (c *Collector) Int32(key string, options ...Option) (n int32) {
+	n, err := c.reader.Int32(key, options...)
+	c.collect(err)
+	return n
+}
+
+// Int64 returns an `int64` from the value found at the given key,
+// as the Reader Int64 method, collecting its eventual error.
+func (c *Collector) Int64(key string, options ...Option) (n int64) {
+	n, err := c.reader.Int64(key, options...)
+	c.collect(err)
+	return n
+}
+
+// Uint returns a `uint` from the value found at the given key,
+// as the Reader Uint method, collecting its eventual error.
+func (c *Collector) Uint(key string, options ...Option) (n uint) {
+	n, err := c.reader.Uint(key, options...)
+	c.collect(err)
+	return n
+}
+
+// Uint8 returns a `uint8` from the value found at the given key,
+// as the Reader Uint8 method, collecting its eventual error.
+func (c *Collector) Uint8(key string, options ...Option) (n uint8) {
+	n, err := c.reader.Uint8(key, options...)
+	c.collect(err)
+	return n
+}
+
+// Uint16 returns a `uint16` from the value found at the given key,
+// as the Reader Uint16 method, collecting its eventual error.
+func (c *Collector) Uint16(key string, options ...Option) (n uint16) {
+	n, err := c.reader.Uint16(key, options...)
+	c.collect(err)
+	return n
+}
+
+// Uint32 returns a `uint32` from the value found at the given key,
+// as the Reader Uint32 method, collecting its eventual error.
+func (c *Collector) Uint32(key string, options ...Option) (n uint32) {
+	n, err := c.reader.Uint32(key, options...)
+	c.collect(err)
+	return n
+}
+
+// Uint64 returns a `uint64` from the value found at the given key,
+// as the Reader Uint64 method, collecting its eventual error.
+func (c *Collector) Uint64(key string, options ...Option) (n uint64) {
+	n, err := c.reader.Uint64(key, options...)
+	c.collect(err)
+	return n
+}
+
+// Float32 returns a `float32` from the value found at the given key,
+// as the Reader Float32 method, collecting its eventual error.
+func (c *Collector) Float32(key string, options ...Option) (f float32) {
+	f, err := c.reader.Float32(key, options...)
+	c.collect(err)
+	return f
+}
+
+// Float64 returns a `float64` from the value found at the given key,
+// as the Reader Float64 method, collecting its eventual error.
+func (c *Collector) Float64(key string, options ...Option) (f float64) {
+	f, err := c.reader.Float64(key, options...)
+	c.collect(err)
+	return f
+}
+
+// BoolPtr returns a `*bool` from the value found at the given key,
+// as the Reader BoolPtr method, collecting its eventual error.
+func (c *Collector) BoolPtr(key string, options ...Option) (boolPtr *bool) {
+	boolPtr, err := c.reader.BoolPtr(key, options...)
+	c.collect(err)
+	return boolPtr
+}
+
+// IntPtr returns an `*int` from the value found at the given key,
+// as the Reader IntPtr method, collecting its eventual error.
+func (c *Collector) IntPtr(key string, options ...Option) (intPtr *int) {
+	intPtr, err := c.reader.IntPtr(key, options...)
+	c.collect(err)
+	return intPtr
+}
+
+// Int8Ptr returns an `*int8` from the value found at the given key,
+// as the Reader Int8Ptr method, collecting its eventual error.
+func (c *Collector) Int8Ptr(key string, options ...Option) (int8Ptr *int8) {
+	int8Ptr, err := c.reader.Int8Ptr(key, options...)
+	c.collect(err)
+	return int8Ptr
+}
+
+// Int16Ptr returns an `*int16` from the value found at the given key,
+// as the Reader Int16Ptr method, collecting its eventual error.
+func (c *Collector) Int16Ptr(key string, options ...Option) (int16Ptr *int16) {
+	int16Ptr, err := c.reader.Int16Ptr(key, options...)
+	c.collect(err)
+	return int16Ptr
+}
+
+// Int32Ptr returns an `*int32` from the value found at the given key,
+// as the Reader Int32Ptr method, collecting its eventual error.
+func (c *Collector) Int32Ptr(key string, options ...Option) (int32Ptr *int32) {
+	int32Ptr, err := c.reader.Int32Ptr(key, options...)
+	c.collect(err)
+	return int32Ptr
+}
+
+// Int64Ptr returns an `*int64` from the value found at the given key,
+// as the Reader Int64Ptr method, collecting its eventual error.
+func (c *Collector) Int64Ptr(key string, options ...Option) (int64Ptr *int64) {
+	int64Ptr, err := c.reader.Int64Ptr(key, options...)
+	c.collect(err)
+	return int64Ptr
+}
+
+// UintPtr returns a `*uint` from the value found at the given key,
+// as the Reader UintPtr method, collecting its eventual error.
+func (c *Collector) UintPtr(key string, options ...Option) (uintPtr *uint) {
+	uintPtr, err := c.reader.UintPtr(key, options...)
+	c.collect(err)
+	return uintPtr
+}
+
+// Uint8Ptr returns a `*uint8` from the value found at the given key,
+// as the Reader Uint8Ptr method, collecting its eventual error.
+func (c *Collector) Uint8Ptr(key string, options ...Option) (uint8Ptr *uint8) {
+	uint8Ptr, err := c.reader.Uint8Ptr(key, options...)
+	c.collect(err)
+	return uint8Ptr
+}
+
+// Uint16Ptr returns a `*uint16` from the value found at the given key,
+// as the Reader Uint16Ptr method, collecting its eventual error.
+func (c *Collector) Uint16Ptr(key string, options ...Option) (uint16Ptr *uint16) {
+	uint16Ptr, err := c.reader.Uint16Ptr(key, options...)
+	c.collect(err)
+	return uint16Ptr
+}
+
+// Uint32Ptr returns a `*uint32` from the value found at the given key,
+// as the Reader Uint32Ptr method, collecting its eventual error.
+func (c *Collector) Uint32Ptr(key string, options ...Option) (uint32Ptr *uint32) {
+	uint32Ptr, err := c.reader.Uint32Ptr(key, options...)
+	c.collect(err)
+	return uint32Ptr
+}
+
+// Uint64Ptr returns a `*uint64` from the value found at the given key,
+// as the Reader Uint64Ptr method, collecting its eventual error.
+func (c *Collector) Uint64Ptr(key string, options ...Option) (uint64Ptr *uint64) {
+	uint64Ptr, err := c.reader.Uint64Ptr(key, options...)
+	c.collect(err)
+	return uint64Ptr
+}
+
+// Float32Ptr returns a `*float32` from the value found at the given key,
+// as the Reader Float32Ptr method, collecting its eventual error.
+func (c *Collector) Float32Ptr(key string, options ...Option) (float32Ptr *float32) {
+	float32Ptr, err := c.reader.Float32Ptr(key, options...)
+	c.collect(err)
+	return float32Ptr
+}
+
+// Float64Ptr returns a `*float64` from the value found at the given key,
+// as the Reader Float64Ptr method, collecting its eventual error.
+func (c *Collector) Float64Ptr(key string, options ...Option) (float64Ptr *float64) {
+	float64Ptr, err := c.reader.Float64Ptr(key, options...)
+	c.collect(err)
+	return float64Ptr
+}
+
+// CSVInt returns an `[]int` from the value found at the given key,
+// as the Reader CSVInt method, collecting its eventual error.
+func (c *Collector) CSVInt(key string, options ...Option) (values []int) {
+	values, err := c.reader.CSVInt(key, options...)
+	c.collect(err)
+	return values
+}
+
+// CSVInt8 returns an `[]int8` from the value found at the given key,
+// as the Reader CSVInt8 method, collecting its eventual error.
+func (c *Collector) CSVInt8(key string, options ...Option) (values []int8) {
+	values, err := c.reader.CSVInt8(key, options...)
+	c.collect(err)
+	return values
+}
+
+// CSVInt16 returns an `[]int16` from the value found at the given key,
+// as the Reader CSVInt16 method, collecting its eventual error.
+func (c *Collector) CSVInt16(key string, options ...Option) (values []int16) {
+	values, err := c.reader.CSVInt16(key, options...)
+	c.collect(err)
+	return values
+}
+
+// CSVInt32 returns an `[]int32` from the value found at the given key,
+// as the Reader CSVInt32 method, collecting its eventual error.
+func (c *Collector) CSVInt32(key string, options ...Option) (values []int32) {
+	values, err := c.reader.CSVInt32(key, options...)
+	c.collect(err)
+	return values
+}
+
+// CSVInt64 returns an `[]int64` from the value found at the given key,
+// as the Reader CSVInt64 method, collecting its eventual error.
+func (c *Collector) CSVInt64(key string, options ...Option) (values []int64) {
+	values, err := c.reader.CSVInt64(key, options...)
+	c.collect(err)
+	return values
+}
+
+// CSVUint returns a `[]uint` from the value found at the given key,
+// as the Reader CSVUint method, collecting its eventual error.
+func (c *Collector) CSVUint(key string, options ...Option) (values []uint) {
+	values, err := c.reader.CSVUint(key, options...)
+	c.collect(err)
+	return values
+}
+
+// CSVUint8 returns a `[]uint8` from the value found at the given key,
+// as the Reader CSVUint8 method, collecting its eventual error.
+func (c *Collector) CSVUint8(key string, options ...Option) (values []uint8) {
+	values, err := c.reader.CSVUint8(key, options...)
+	c.collect(err)
+	return values
+}
+
+// CSVUint16 returns a `[]uint16` from the value found at the given key,
+// as the Reader CSVUint16 method, collecting its eventual error.
+func (c *Collector) CSVUint16(key string, options ...Option) (values []uint16) {
+	values, err := c.reader.CSVUint16(key, options...)
+	c.collect(err)
+	return values
+}
+
+// CSVUint32 returns a `[]uint32` from the value found at the given key,
+// as the Reader CSVUint32 method, collecting its eventual error.
+func (c *Collector) CSVUint32(key string, options ...Option) (values []uint32) {
+	values, err := c.reader.CSVUint32(key, options...)
+	c.collect(err)
+	return values
+}
+
+// CSVUint64 returns a `[]uint64` from the value found at the given key,
+// as the Reader CSVUint64 method, collecting its eventual error.
+func (c *Collector) CSVUint64(key string, options ...Option) (values []uint64) {
+	values, err := c.reader.CSVUint64(key, options...)
+	c.collect(err)
+	return values
+}
+
+// NetipAddr returns a `netip.Addr` from the value found at the given key,
+// as the Reader NetipAddr method, collecting its eventual error.
+func (c *Collector) NetipAddr(key string, options ...Option) (addr netip.Addr) {
+	addr, err := c.reader.NetipAddr(key, options...)
+	c.collect(err)
+	return addr
+}
+
+// NetipAddrPort returns a `netip.AddrPort` from the value found at the given key,
+// as the Reader NetipAddrPort method, collecting its eventual error.
+func (c *Collector) NetipAddrPort(key string, options ...Option) (addrPort netip.AddrPort) {
+	addrPort, err := c.reader.NetipAddrPort(key, options...)
+	c.collect(err)
+	return addrPort
+}
+
+// NetipPrefix returns a `netip.Prefix` from the value found at the given key,
+// as the Reader NetipPrefix method, collecting its eventual error.
+func (c *Collector) NetipPrefix(key string, options ...Option) (prefix netip.Prefix) {
+	prefix, err := c.reader.NetipPrefix(key, options...)
+	c.collect(err)
+	return prefix
+}
+
+// CSVNetipAddresses returns a `[]netip.Addr` from the value found at the given key,
+// as the Reader CSVNetipAddresses method, collecting its eventual error.
+func (c *Collector) CSVNetipAddresses(key string, options ...Option) (addresses []netip.Addr) {
+	addresses, err := c.reader.CSVNetipAddresses(key, options...)
+	c.collect(err)
+	return addresses
+}
+
+// CSVNetipAddrPorts returns a `[]netip.AddrPort` from the value found at the given key,
+// as the Reader CSVNetipAddrPorts method, collecting its eventual error.
+func (c *Collector) CSVNetipAddrPorts(key string, options ...Option) (addrPorts []netip.AddrPort) {
+	addrPorts, err := c.reader.CSVNetipAddrPorts(key, options...)
+	c.collect(err)
+	return addrPorts
+}
+
+// CSVNetipPrefixes returns a `[]netip.Prefix` from the value found at the given key,
+// as the Reader CSVNetipPrefixes method, collecting its eventual error.
+func (c *Collector) CSVNetipPrefixes(key string, options ...Option) (prefixes []netip.Prefix) {
+	prefixes, err := c.reader.CSVNetipPrefixes(key, options...)
+	c.collect(err)
+	return prefixes
+}
+
+// DurationPtr returns a `*time.Duration` from the value found at the given key,
+// as the Reader DurationPtr method, collecting its eventual error.
+func (c *Collector) DurationPtr(key string, options ...Option) (durationPtr *time.Duration) {
+	durationPtr, err := c.reader.DurationPtr(key, options...)
+	c.collect(err)
+	return durationPtr
+}
+
+// Duration returns a `time.Duration` from the value found at the given key,
+// as the Reader Duration method, collecting its eventual error.
+func (c *Collector) Duration(key string, options ...Option) (duration time.Duration) {
+	duration, err := c.reader.Duration(key, options...)
+	c.collect(err)
+	return duration
+}
+
+// TimePtr returns a `*time.Time` from the value found at the given key,
+// as the Reader TimePtr method, collecting its eventual error.
+func (c *Collector) TimePtr(key string, options ...Option) (timePtr *time.Time) {
+	timePtr, err := c.reader.TimePtr(key, options...)
+	c.collect(err)
+	return timePtr
+}
+
+// Time returns a `time.Time` from the value found at the given key,
+// as the Reader Time method, collecting its eventual error.
+func (c *Collector) Time(key string, options ...Option) (t time.Time) {
+	t, err := c.reader.Time(key, options...)
+	c.collect(err)
+	return t
+}
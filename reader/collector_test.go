@@ -0,0 +1,61 @@
+package reader
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/qdm12/gosettings/validate"
+)
+
+func Test_Collector(t *testing.T) {
+	t.Parallel()
+
+	source := &testSource{keyValue: map[string]string{
+		"PORT":    "8000",
+		"SMALL":   "300",
+		"ENABLED": "maybe",
+		"NAME":    "name",
+	}}
+	collector := NewCollector(New(Settings{Sources: []Source{source}}))
+
+	port := collector.Uint16("PORT")
+	small := collector.Uint8("SMALL")
+	_ = collector.BoolPtr("ENABLED")
+	name := collector.String("NAME")
+
+	if port != 8000 {
+		t.Errorf("expected port 8000, got %d", port)
+	}
+	if small != 0 {
+		t.Errorf("expected small 0, got %d", small)
+	}
+	if name != "name" {
+		t.Errorf("expected name %q, got %q", "name", name)
+	}
+
+	err := collector.Err()
+	if !errors.Is(err, ErrValueNotInRange) {
+		t.Errorf("expected error %v to wrap %v", err, ErrValueNotInRange)
+	}
+	if !errors.Is(err, validate.ErrValueNotOneOf) {
+		t.Errorf("expected error %v to wrap %v", err, validate.ErrValueNotOneOf)
+	}
+	const expectedErrMessage = "test SMALL: value is not in range: 300 is not between 0 and 255\n" +
+		"test ENABLED: value is not one of the possible choices: " +
+		"maybe must be one of enabled, yes, on, true, disabled, no, off or false"
+	if err == nil || err.Error() != expectedErrMessage {
+		t.Errorf("expected error message %q, got %v", expectedErrMessage, err)
+	}
+}
+
+func Test_Collector_noError(t *testing.T) {
+	t.Parallel()
+
+	collector := NewCollector(New(Settings{Sources: []Source{&testSource{}}}))
+	_ = collector.Int("MISSING")
+
+	err := collector.Err()
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
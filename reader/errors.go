@@ -0,0 +1,14 @@
+package reader
+
+import (
+	"github.com/qdm12/gosettings/internal/parse"
+)
+
+// Sentinel errors wrapped by the errors returned by the reader
+// methods, which can be checked using errors.Is.
+var (
+	ErrValueNotInRange    = parse.ErrValueNotInRange
+	ErrExpansionNotClosed = parse.ErrExpansionNotClosed
+	ErrExpansionKeyEmpty  = parse.ErrExpansionKeyEmpty
+	ErrExpansionCycle     = parse.ErrExpansionCycle
+)
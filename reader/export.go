@@ -0,0 +1,219 @@
+package reader
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/gosettings"
+	"github.com/qdm12/gosettings/internal/parse"
+	"github.com/qdm12/gosettings/reader/sources/env"
+)
+
+// Markdown returns a Markdown table documenting all the registered
+// keys, in their registration order. Each key is shown in the form
+// of each reader source implementing the KeyFormatter interface,
+// and secret default values are obfuscated.
+func (r *Reader) Markdown() string {
+	lines := []string{
+		"| Key | Type | Default | Allowed values | Deprecated keys | Description |",
+		"| --- | --- | --- | --- | --- | --- |",
+	}
+	for _, keyInfo := range r.KeyInfos() {
+		defaultValue := keyInfo.Default
+		if keyInfo.Secret && defaultValue != "" {
+			defaultValue = gosettings.ObfuscateKey(defaultValue)
+		}
+
+		var retroForms []string
+		for _, retroKey := range keyInfo.RetroKeys {
+			retroForms = append(retroForms, r.formatKey(retroKey)...)
+		}
+
+		cells := []string{
+			markdownCode(r.formatKey(keyInfo.Key)...),
+			markdownEscape(keyInfo.Type),
+			markdownCode(defaultValue),
+			markdownCode(keyInfo.AllowedValues...),
+			markdownCode(retroForms...),
+			markdownEscape(keyInfo.Description),
+		}
+		lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func markdownCode(values ...string) string {
+	codes := make([]string, 0, len(values))
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		codes = append(codes, "`"+markdownEscape(value)+"`")
+	}
+	return strings.Join(codes, ", ")
+}
+
+func markdownEscape(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+type jsonSchema struct {
+	Schema     string                        `json:"$schema"`
+	Type       string                        `json:"type"`
+	Properties map[string]jsonSchemaProperty `json:"properties"`
+}
+
+type jsonSchemaProperty struct {
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+	Default     any    `json:"default,omitempty"`
+	Enum        []any  `json:"enum,omitempty"`
+	Deprecated  bool   `json:"deprecated,omitempty"`
+	WriteOnly   bool   `json:"writeOnly,omitempty"`
+}
+
+// JSONSchema returns a JSON Schema document describing an object
+// with a property for each registered key, as well as a deprecated
+// property for each of their retro-compatible keys.
+// The key types `bool` and `boolean` are mapped to the JSON type
+// `boolean`, integer Go types and `integer` to `integer`, float
+// Go types and `number` to `number`, and all other types to `string`.
+// Default values of secret keys are not included.
+func (r *Reader) JSONSchema() (document []byte, err error) {
+	schema := jsonSchema{
+		Schema:     "https://json-schema.org/draft/2020-12/schema",
+		Type:       "object",
+		Properties: make(map[string]jsonSchemaProperty),
+	}
+
+	for _, keyInfo := range r.KeyInfos() {
+		jsonType := toJSONType(keyInfo.Type)
+		property := jsonSchemaProperty{
+			Type:        jsonType,
+			Description: keyInfo.Description,
+			WriteOnly:   keyInfo.Secret,
+		}
+		if keyInfo.Default != "" && !keyInfo.Secret {
+			property.Default, err = toJSONValue(jsonType, keyInfo.Default)
+			if err != nil {
+				return nil, fmt.Errorf("default value of key %s: %w", keyInfo.Key, err)
+			}
+		}
+		for _, allowedValue := range keyInfo.AllowedValues {
+			value, err := toJSONValue(jsonType, allowedValue)
+			if err != nil {
+				return nil, fmt.Errorf("allowed value of key %s: %w", keyInfo.Key, err)
+			}
+			property.Enum = append(property.Enum, value)
+		}
+		schema.Properties[keyInfo.Key] = property
+
+		for _, retroKey := range keyInfo.RetroKeys {
+			retroProperty := property
+			retroProperty.Description = "Deprecated: use " + keyInfo.Key + " instead."
+			retroProperty.Default = nil
+			retroProperty.Deprecated = true
+			schema.Properties[retroKey] = retroProperty
+		}
+	}
+
+	document, err = json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding JSON schema: %w", err)
+	}
+	return append(document, '\n'), nil
+}
+
+func toJSONType(keyType string) (jsonType string) {
+	switch keyType {
+	case "bool", "boolean":
+		return "boolean"
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "integer":
+		return "integer"
+	case "float32", "float64", "number":
+		return "number"
+	default:
+		return "string"
+	}
+}
+
+func toJSONValue(jsonType, value string) (jsonValue any, err error) {
+	switch jsonType {
+	case "boolean":
+		return strconv.ParseBool(value)
+	case "integer":
+		return strconv.ParseInt(value, 10, 64)
+	case "number":
+		return strconv.ParseFloat(value, 64)
+	default:
+		return value, nil
+	}
+}
+
+// DotEnv returns a sample dotenv file content with a variable for
+// each registered key, in their registration order, set to its
+// default value and preceded by comments describing the key.
+// Variable names are in the form of the first environment variable
+// source of the reader, including one wrapped by another source such
+// as the reload source, or in the form of an environment variable
+// source without prefix if the reader has no such source.
+// Secret keys have an empty value.
+func (r *Reader) DotEnv() string {
+	var envSource parse.Source = new(env.Source)
+	for _, source := range r.sources {
+		// Sources are matched by name to find wrapped
+		// environment variable sources.
+		if source.String() == envSource.String() {
+			envSource = source
+			break
+		}
+	}
+
+	keyInfos := r.KeyInfos()
+	blocks := make([]string, 0, len(keyInfos))
+	for _, keyInfo := range keyInfos {
+		var lines []string
+		if keyInfo.Description != "" {
+			for _, descriptionLine := range strings.Split(keyInfo.Description, "\n") {
+				lines = append(lines, "# "+descriptionLine)
+			}
+		}
+		if keyInfo.Type != "" {
+			lines = append(lines, "# Type: "+keyInfo.Type)
+		}
+		if len(keyInfo.AllowedValues) > 0 {
+			lines = append(lines, "# Allowed values: "+strings.Join(keyInfo.AllowedValues, ", "))
+		}
+		if len(keyInfo.RetroKeys) > 0 {
+			retroKeys := make([]string, len(keyInfo.RetroKeys))
+			for i, retroKey := range keyInfo.RetroKeys {
+				retroKeys[i] = envSource.KeyTransform(retroKey)
+			}
+			lines = append(lines, "# Deprecated keys: "+strings.Join(retroKeys, ", "))
+		}
+
+		value := keyInfo.Default
+		if keyInfo.Secret {
+			lines = append(lines, "# Secret: set it outside of version control.")
+			value = ""
+		}
+		lines = append(lines, envSource.KeyTransform(keyInfo.Key)+"="+dotEnvQuote(value))
+		blocks = append(blocks, strings.Join(lines, "\n"))
+	}
+	return strings.Join(blocks, "\n\n") + "\n"
+}
+
+// dotEnvQuote double quotes the value if it contains characters
+// which would not be parsed literally as an unquoted dotenv value.
+func dotEnvQuote(value string) string {
+	if !strings.ContainsAny(value, " \t\n\r#\"'\\$") {
+		return value
+	}
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`,
+		"\r", `\r`, "\t", `\t`, "$", `\$`)
+	return `"` + replacer.Replace(value) + `"`
+}
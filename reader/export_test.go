@@ -0,0 +1,234 @@
+package reader
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/qdm12/gosettings/reader/sources/dotenv"
+	"github.com/qdm12/gosettings/reader/sources/env"
+	"github.com/qdm12/gosettings/reader/sources/flag"
+	"github.com/qdm12/gosettings/reader/sources/reload"
+)
+
+func newExportTestReader() *Reader {
+	reader := New(Settings{
+		Sources: []Source{
+			flag.New(nil),
+			env.New(env.Settings{KeyPrefix: "APP_"}),
+		},
+	})
+	reader.Register(
+		KeyInfo{
+			Key:         "SERVER_ADDRESS",
+			Description: "Listening address.\nIt can contain a | pipe.",
+			Type:        "string",
+			Default:     "localhost:8000",
+			RetroKeys:   []string{"LISTENING_ADDRESS"},
+		},
+		KeyInfo{
+			Key:           "LOG_LEVEL",
+			Type:          "string",
+			Default:       "info",
+			AllowedValues: []string{"debug", "info"},
+		},
+		KeyInfo{
+			Key:     "PORT",
+			Type:    "uint16",
+			Default: "8000",
+		},
+		KeyInfo{
+			Key:     "GREETING",
+			Default: `hello "world" #1`,
+		},
+		KeyInfo{
+			Key:     "API_KEY",
+			Default: "abc",
+			Secret:  true,
+		},
+	)
+	return reader
+}
+
+func Test_Reader_Markdown(t *testing.T) {
+	t.Parallel()
+
+	reader := newExportTestReader()
+
+	markdown := reader.Markdown()
+
+	const expected = "| Key | Type | Default | Allowed values | Deprecated keys | Description |\n" +
+		"| --- | --- | --- | --- | --- | --- |\n" +
+		"| `--server-address`, `APP_SERVER_ADDRESS` | string | `localhost:8000` |  | " +
+		"`--listening-address`, `APP_LISTENING_ADDRESS` | Listening address.<br>It can contain a \\| pipe. |\n" +
+		"| `--log-level`, `APP_LOG_LEVEL` | string | `info` | `debug`, `info` |  |  |\n" +
+		"| `--port`, `APP_PORT` | uint16 | `8000` |  |  |  |\n" +
+		"| `--greeting`, `APP_GREETING` |  | `hello \"world\" #1` |  |  |  |\n" +
+		"| `--api-key`, `APP_API_KEY` |  | `[set]` |  |  |  |\n"
+	if markdown != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, markdown)
+	}
+}
+
+func Test_Reader_JSONSchema(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		reader := newExportTestReader()
+
+		document, err := reader.JSONSchema()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		const expected = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "properties": {
+    "API_KEY": {
+      "type": "string",
+      "writeOnly": true
+    },
+    "GREETING": {
+      "type": "string",
+      "default": "hello \"world\" #1"
+    },
+    "LISTENING_ADDRESS": {
+      "type": "string",
+      "description": "Deprecated: use SERVER_ADDRESS instead.",
+      "deprecated": true
+    },
+    "LOG_LEVEL": {
+      "type": "string",
+      "default": "info",
+      "enum": [
+        "debug",
+        "info"
+      ]
+    },
+    "PORT": {
+      "type": "integer",
+      "default": 8000
+    },
+    "SERVER_ADDRESS": {
+      "type": "string",
+      "description": "Listening address.\nIt can contain a | pipe.",
+      "default": "localhost:8000"
+    }
+  }
+}
+`
+		if string(document) != expected {
+			t.Errorf("expected:\n%s\ngot:\n%s", expected, document)
+		}
+	})
+
+	t.Run("default_not_valid", func(t *testing.T) {
+		t.Parallel()
+
+		reader := New(Settings{Sources: []Source{&testSource{}}})
+		reader.Register(KeyInfo{Key: "ENABLED", Type: "bool", Default: "maybe"})
+
+		_, err := reader.JSONSchema()
+
+		if !errors.Is(err, strconv.ErrSyntax) {
+			t.Errorf("expected error %v to wrap %v", err, strconv.ErrSyntax)
+		}
+		const expectedMessage = `default value of key ENABLED: strconv.ParseBool: parsing "maybe": invalid syntax`
+		if err.Error() != expectedMessage {
+			t.Errorf("expected error message %q but got %q", expectedMessage, err.Error())
+		}
+	})
+}
+
+func Test_Reader_DotEnv(t *testing.T) {
+	t.Parallel()
+
+	reader := newExportTestReader()
+
+	content := reader.DotEnv()
+
+	const expected = `# Listening address.
+# It can contain a | pipe.
+# Type: string
+# Deprecated keys: APP_LISTENING_ADDRESS
+APP_SERVER_ADDRESS=localhost:8000
+
+# Type: string
+# Allowed values: debug, info
+APP_LOG_LEVEL=info
+
+# Type: uint16
+APP_PORT=8000
+
+APP_GREETING="hello \"world\" #1"
+
+# Secret: set it outside of version control.
+APP_API_KEY=
+`
+	if content != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, content)
+	}
+
+	path := filepath.Join(t.TempDir(), ".env")
+	err := os.WriteFile(path, []byte(content), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, err := dotenv.New(dotenv.Settings{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, _ := source.Get("APP_GREETING")
+	if value != `hello "world" #1` {
+		t.Errorf("expected sample dotenv value to parse back, got %q", value)
+	}
+}
+
+func Test_Reader_DotEnv_envSource(t *testing.T) {
+	t.Parallel()
+
+	wrappedEnvSource, err := reload.New(reload.Settings{
+		Load: func() (source reload.Wrapped, err error) {
+			return env.New(env.Settings{KeyPrefix: "APP_"}), nil
+		},
+		Paths: []string{"unused"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		sources  []Source
+		expected string
+	}{
+		"no_env_source": {
+			sources:  []Source{flag.New(nil)},
+			expected: "SERVER_ADDRESS=:8000\n",
+		},
+		"wrapped_env_source": {
+			sources:  []Source{flag.New(nil), wrappedEnvSource},
+			expected: "APP_SERVER_ADDRESS=:8000\n",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			reader := New(Settings{Sources: testCase.sources})
+			reader.Register(KeyInfo{Key: "server-address", Default: ":8000"})
+
+			content := reader.DotEnv()
+
+			if content != testCase.expected {
+				t.Errorf("expected %q but got %q", testCase.expected, content)
+			}
+		})
+	}
+}
@@ -14,3 +14,83 @@ type Source interface {
 	// the flags source.
 	KeyTransform(key string) string
 }
+
+// Locator is an optional interface a Source can implement
+// to give the location of a key in the source, such as a
+// line number in a file. This location is then added to
+// error messages, for example:
+// TOML file config.toml KEY (line 5): some problem
+type Locator interface {
+	// Locate returns the location of the given key transformed
+	// by the source KeyTransform method, or the empty string if
+	// the location is not known.
+	Locate(key string) (location string)
+}
+
+// KeyLister is an optional interface a Source can implement
+// to list all the keys it contains, such that keys never
+// queried can be detected with the Reader UnusedKeys method.
+type KeyLister interface {
+	// Keys returns all the keys set in the source, in
+	// the form given by the source KeyTransform method.
+	Keys() (keys []string)
+}
+
+// Strict is an optional interface a Source can implement
+// to have its keys never queried considered as errors by
+// the Reader CheckUnusedKeys method.
+type Strict interface {
+	// Strict returns true if unused keys in the source
+	// should be considered as errors.
+	Strict() bool
+}
+
+// Reloader is an optional interface a Source can implement to
+// be re-read when its files change, using the Reader Watch method.
+// It is notably implemented by the source of the subpackage
+// github.com/qdm12/gosettings/reader/sources/reload.
+type Reloader interface {
+	// Reload re-reads the source and returns the keys, in the form
+	// given by the source KeyTransform method, which were added,
+	// removed or had their value changed. If an error occurs, the
+	// source must keep serving its previous values.
+	Reload() (changedKeys []string, err error)
+	// WatchPaths returns the file and directory paths read by
+	// the source, to be watched for changes.
+	WatchPaths() (paths []string)
+}
+
+// KeyFormatter is an optional interface a Source can implement to
+// show its form of keys in the usage text of the Reader Usage method.
+// It is notably implemented by the flag and environment variable
+// sources.
+type KeyFormatter interface {
+	// FormatKey returns the key as a user would set it in the
+	// source, for example `--server-address` for a flag source.
+	FormatKey(key string) (formatted string)
+}
+
+// MultiGetter is an optional interface a Source can implement to
+// return all the values set for a key, such as for a flag given
+// more than once. The Reader CSV and CSV* methods then concatenate
+// the comma separated values of all the occurrences of the key,
+// so a list can be given either as comma separated values or by
+// repeating the key. It is notably implemented by the flag source.
+type MultiGetter interface {
+	// GetAll returns all the values set for the given key, in
+	// the form given by the source KeyTransform method, in order.
+	// It returns nil if the key is not set.
+	GetAll(key string) (values []string)
+}
+
+// ErrorGetter is an optional interface a Source can implement to
+// report an error getting the value of a key, such as a value file
+// which cannot be read. It is then used instead of the Get method,
+// and its error is returned by the Reader methods returning an error.
+// It is notably implemented by the environment variable source.
+type ErrorGetter interface {
+	// GetWithError returns the value of the given key, in the form
+	// given by the source KeyTransform method, whether it is set,
+	// and an error if the value cannot be read.
+	GetWithError(key string) (value string, isSet bool, err error)
+}
@@ -0,0 +1,46 @@
+package reader
+
+import (
+	"github.com/qdm12/gosettings/internal/parse"
+)
+
+// LookupResult contains the value found at a key
+// together with its provenance.
+type LookupResult struct {
+	// IsSet is true if the key is set in one of the sources.
+	// All other fields are left empty if it is false.
+	IsSet bool
+	// RawValue is the value as found in the source,
+	// before any processing.
+	RawValue string
+	// Value is the value after processing, as it would
+	// be returned by the String method.
+	Value string
+	// Source is the name of the source the value was
+	// found in, for example 'environment variable'.
+	Source string
+	// Key is the key the value was found at, as transformed
+	// by the source, for example SERVER_ADDRESS for the
+	// environment variable source.
+	Key string
+	// Location is the location of the key in the source, if
+	// the source implements the Locator interface, and is
+	// empty otherwise. For example 'line 5'.
+	Location string
+	// Deprecated is true if the value was found at a
+	// deprecated key instead of the current key.
+	Deprecated bool
+}
+
+// Lookup returns the value found at the given key together
+// with its provenance, which can be used to log where each
+// setting value comes from.
+// The key is considered as not set in the same cases as
+// the Get method, and the value is processed in the same
+// way as the String method.
+// An error is returned in the same cases as the GetWithError method.
+func (r *Reader) Lookup(key string, options ...Option) (result LookupResult, err error) {
+	parseOptions := r.makeParseOptions(options)
+	parseResult, err := parse.Lookup(r.sources, key, parseOptions...)
+	return LookupResult(parseResult), err
+}
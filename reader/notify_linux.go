@@ -0,0 +1,90 @@
+//go:build linux
+
+package reader
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// newNotifier returns a channel receiving a value when a file system
+// event occurs for the paths given, using inotify. File paths are
+// watched through their parent directory, to detect files replaced
+// by a rename, and directory paths are watched recursively.
+// The stop function must be called to release resources.
+func newNotifier(paths []string) (notifications <-chan struct{},
+	stop func(), err error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, nil, fmt.Errorf("initializing inotify: %w", err)
+	}
+	// The file descriptor is non blocking so reads are handled by
+	// the Go runtime poller, and closing the file unblocks reads.
+	file := os.NewFile(uintptr(fd), "inotify")
+
+	const mask = syscall.IN_CREATE | syscall.IN_DELETE | syscall.IN_MODIFY |
+		syscall.IN_CLOSE_WRITE | syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO |
+		syscall.IN_ATTRIB | syscall.IN_DELETE_SELF | syscall.IN_MOVE_SELF
+	for _, directory := range watchDirectories(paths) {
+		_, err = syscall.InotifyAddWatch(fd, directory, mask)
+		if err != nil {
+			_ = file.Close()
+			return nil, nil, fmt.Errorf("watching directory %s: %w", directory, err)
+		}
+	}
+
+	channel := make(chan struct{}, 1)
+	go func() {
+		const bufferSize = 4096
+		buffer := make([]byte, bufferSize)
+		for {
+			_, err := file.Read(buffer)
+			if err != nil { // file closed
+				return
+			}
+			select {
+			case channel <- struct{}{}:
+			default: // a notification is already pending
+			}
+		}
+	}()
+
+	stop = func() { _ = file.Close() }
+	return channel, stop, nil
+}
+
+// watchDirectories returns the deduplicated directories to watch
+// for the paths given. Paths which do not exist are watched through
+// their parent directory, if it exists.
+func watchDirectories(paths []string) (directories []string) {
+	seen := make(map[string]struct{}, len(paths))
+	add := func(directory string) {
+		if _, ok := seen[directory]; ok {
+			return
+		}
+		seen[directory] = struct{}{}
+		directories = append(directories, directory)
+	}
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil || !info.IsDir() {
+			parent := filepath.Dir(path)
+			if info, err := os.Stat(parent); err == nil && info.IsDir() {
+				add(parent)
+			}
+			continue
+		}
+
+		_ = filepath.WalkDir(path, func(path string, entry fs.DirEntry, err error) error {
+			if err == nil && entry.IsDir() {
+				add(path)
+			}
+			return nil
+		})
+	}
+	return directories
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package reader
+
+// newNotifier returns a nil channel on platforms other than
+// Linux, such that only polling is used to detect changes.
+func newNotifier(paths []string) (notifications <-chan struct{},
+	stop func(), err error) {
+	return nil, func() {}, nil
+}
@@ -28,6 +28,23 @@ func AcceptEmpty(accept bool) Option {
 	}
 }
 
+// Expand, if set to true, expands `${KEY}` and `${KEY:-default}`
+// references in the values read, resolving the referenced keys
+// through the reader sources in order of priority.
+// The default value is used if the referenced key is unset or empty,
+// and `$$` can be used to write a literal `$`.
+// Reference cycles result in an error listing the chain of keys.
+// The Get, String and CSV methods ignore this error and return the
+// value without expansion if it cannot be expanded, whereas their
+// GetWithError, StringWithError and CSVWithError variants and the
+// other methods return the error.
+// By default, values are not expanded.
+func Expand(expand bool) Option {
+	return func(s *settings) {
+		s.expand = &expand
+	}
+}
+
 // RetroKeys specifies a list of keys that are deprecated
 // and replaced by the current key.
 // The oldest retro-compatible key should be placed first
@@ -43,11 +60,22 @@ func RetroKeys(retroKeys ...string) Option {
 	}
 }
 
+// Secret marks the value read as secret, such that it is
+// obfuscated using gosettings.ObfuscateKey in the record of
+// the reader, if the reader is recording.
+func Secret() Option {
+	return func(s *settings) {
+		s.secret = true
+	}
+}
+
 type settings struct {
 	forceLowercase *bool
 	acceptEmpty    *bool
+	expand         *bool
 	currentKey     string
 	retroKeys      []string
+	secret         bool
 }
 
 // IsRetro indicates that all the keys given to the reader function
@@ -63,8 +91,10 @@ func (s settings) copy() settings {
 	return settings{
 		forceLowercase: gosettings.CopyPointer(s.forceLowercase),
 		acceptEmpty:    gosettings.CopyPointer(s.acceptEmpty),
+		expand:         gosettings.CopyPointer(s.expand),
 		retroKeys:      gosettings.CopySlice(s.retroKeys),
 		currentKey:     s.currentKey,
+		secret:         s.secret,
 	}
 }
 
@@ -74,7 +104,7 @@ func (r *Reader) makeParseOptions(options []Option) (parseOptions []parse.Option
 		option(&settings)
 	}
 
-	const maxOptions = 3
+	const maxOptions = 6
 	parseOptions = make([]parse.Option, 0, maxOptions)
 	if settings.forceLowercase != nil {
 		parseOption := parse.ForceLowercase(*settings.forceLowercase)
@@ -84,6 +114,10 @@ func (r *Reader) makeParseOptions(options []Option) (parseOptions []parse.Option
 		parseOption := parse.AcceptEmpty(*settings.acceptEmpty)
 		parseOptions = append(parseOptions, parseOption)
 	}
+	if settings.expand != nil {
+		parseOption := parse.Expand(*settings.expand)
+		parseOptions = append(parseOptions, parseOption)
+	}
 	if len(settings.retroKeys) > 0 {
 		parseOption := parse.RetroKeys(r.handleDeprecatedKey, settings.retroKeys...)
 		parseOptions = append(parseOptions, parseOption)
@@ -92,6 +126,25 @@ func (r *Reader) makeParseOptions(options []Option) (parseOptions []parse.Option
 		parseOption := parse.IsRetro(r.handleDeprecatedKey, settings.currentKey)
 		parseOptions = append(parseOptions, parseOption)
 	}
+	parseOptions = append(parseOptions, parse.Observe(r.observer(settings)))
 
 	return parseOptions
 }
+
+// observer returns a function to be used with the parse.Observe
+// option, to track queried keys and eventually record them.
+func (r *Reader) observer(settings settings) func(key string, result parse.LookupResult) {
+	return func(key string, result parse.LookupResult) {
+		keys := make([]string, 0, 1+len(settings.retroKeys)+1)
+		keys = append(keys, key)
+		keys = append(keys, settings.retroKeys...)
+		if settings.currentKey != "" {
+			keys = append(keys, settings.currentKey)
+		}
+		r.queried.add(keys...)
+
+		if r.record != nil {
+			r.record.add(key, result, settings.secret)
+		}
+	}
+}
@@ -14,6 +14,11 @@ type Reader struct {
 	sources             []parse.Source
 	handleDeprecatedKey func(source, deprecatedKey, currentKey string)
 	defaultReadSettings settings
+	queried             *queriedKeys
+	registered          *registeredKeys
+	// record is nil if recording is disabled.
+	record        *record
+	watchSettings WatchSettings
 }
 
 // New creates a new reader using the settings given.
@@ -30,11 +35,18 @@ func New(readerSettings Settings) *Reader {
 		parseSources[i] = source
 	}
 
-	return &Reader{
+	reader := &Reader{
 		sources:             parseSources,
 		handleDeprecatedKey: readerSettings.HandleDeprecatedKey,
 		defaultReadSettings: defaultReadSettings,
+		queried:             newQueriedKeys(),
+		registered:          newRegisteredKeys(),
+		watchSettings:       readerSettings.Watch,
 	}
+	if readerSettings.Record {
+		reader.record = newRecord()
+	}
+	return reader
 }
 
 // Settings is the settings to create a new reader.
@@ -50,6 +62,13 @@ type Settings struct {
 	// DefaultOptions are the default options to use for every method call.
 	// They default to ForceLowercase(true), AcceptEmpty(false).
 	DefaultOptions []Option
+	// Record, if set to true, makes the reader record every key
+	// queried together with its value and its origin, which can
+	// then be retrieved with the Record method.
+	// It defaults to false.
+	Record bool
+	// Watch contains settings for the Watch method.
+	Watch WatchSettings
 }
 
 func (s *Settings) setDefaults() {
@@ -61,4 +80,5 @@ func (s *Settings) setDefaults() {
 	}
 	s.DefaultOptions = gosettings.DefaultSlice(s.DefaultOptions,
 		[]Option{ForceLowercase(true), AcceptEmpty(false)})
+	s.Watch.setDefaults()
 }
@@ -1,10 +1,17 @@
 package reader
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/qdm12/gosettings/internal/parse"
+	"github.com/qdm12/gosettings/reader/sources/env"
+	"github.com/qdm12/gosettings/reader/sources/flag"
+	"github.com/qdm12/gosettings/reader/sources/secretsdir"
 )
 
 func Test_New(t *testing.T) {
@@ -27,6 +34,18 @@ func Test_New(t *testing.T) {
 		t.Error("handleDeprecatedKey should not be nil")
 	}
 	reader.handleDeprecatedKey = nil
+	if reader.queried == nil {
+		t.Error("queried should not be nil")
+	}
+	reader.queried = nil
+	if reader.registered == nil {
+		t.Error("registered should not be nil")
+	}
+	reader.registered = nil
+	if reader.watchSettings.HandleError == nil {
+		t.Error("watchSettings.HandleError should not be nil")
+	}
+	reader.watchSettings.HandleError = nil
 
 	expectedReader := &Reader{
 		sources: []parse.Source{testSourceA, testSourceB},
@@ -34,9 +53,177 @@ func Test_New(t *testing.T) {
 			forceLowercase: ptrTo(true),
 			acceptEmpty:    ptrTo(false),
 		},
+		watchSettings: WatchSettings{
+			PollInterval: 5 * time.Second,
+			Debounce:     100 * time.Millisecond,
+		},
 	}
 
 	if !reflect.DeepEqual(expectedReader, reader) {
 		t.Errorf("expected: %#v, got: %#v", expectedReader, reader)
 	}
 }
+
+func Test_Reader_CSV_repeatedFlags(t *testing.T) {
+	t.Parallel()
+
+	reader := New(Settings{
+		Sources: []Source{
+			flag.New([]string{"program", "--header", "a,b", "--port=80", "--header=c", "--port", "443",
+				"--tag", "t", "--tag", ""}),
+			env.New(env.Settings{Environ: []string{"HEADER=x", "NAMES=d,e", "TAG=x"}}),
+		},
+	})
+
+	headers, err := reader.CSVWithError("HEADER")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedHeaders := []string{"a", "b", "c"}
+	if !reflect.DeepEqual(headers, expectedHeaders) {
+		t.Errorf("expected %v, got %v", expectedHeaders, headers)
+	}
+
+	names, err := reader.CSVWithError("NAMES")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedNames := []string{"d", "e"}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Errorf("expected %v, got %v", expectedNames, names)
+	}
+
+	tags, err := reader.CSVWithError("TAG")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedTags := []string{"t"}
+	if !reflect.DeepEqual(tags, expectedTags) {
+		t.Errorf("expected %v, got %v", expectedTags, tags)
+	}
+
+	ports, err := reader.CSVUint16("PORT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedPorts := []uint16{80, 443}
+	if !reflect.DeepEqual(ports, expectedPorts) {
+		t.Errorf("expected %v, got %v", expectedPorts, ports)
+	}
+}
+
+func Test_Reader_envFileErrors(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "port")
+	err := os.WriteFile(path, []byte("8000"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	missingPath := filepath.Join(t.TempDir(), "missing")
+
+	testCases := map[string]struct {
+		environ    []string
+		errWrapped error
+		errMessage string
+	}{
+		"value_and_file_set": {
+			environ:    []string{"PORT=80", "PORT_FILE=" + path},
+			errWrapped: env.ErrValueAndFileSet,
+			errMessage: "environment variable PORT: both value and file are set: PORT and PORT_FILE",
+		},
+		"file_not_found": {
+			environ:    []string{"PORT_FILE=" + missingPath},
+			errWrapped: os.ErrNotExist,
+			errMessage: "environment variable PORT: reading file for PORT_FILE: open " +
+				missingPath + ": no such file or directory",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			reader := New(Settings{
+				Sources: []Source{env.New(env.Settings{
+					Environ:    testCase.environ,
+					FileSuffix: "_FILE",
+				})},
+			})
+
+			port, err := reader.Uint16Ptr("PORT")
+
+			if port != nil {
+				t.Errorf("expected nil port, got %d", *port)
+			}
+			if !errors.Is(err, testCase.errWrapped) {
+				t.Errorf("expected error %v to wrap %v", err, testCase.errWrapped)
+			}
+			if err != nil && err.Error() != testCase.errMessage {
+				t.Errorf("expected error message %q, got %q", testCase.errMessage, err.Error())
+			}
+		})
+	}
+}
+
+func Test_Reader_String_expand(t *testing.T) {
+	t.Parallel()
+
+	reader := New(Settings{
+		Sources: []Source{env.New(env.Settings{Environ: []string{
+			"HOST=Example.com",
+			"URL=http://${HOST}",
+			"A=${B}",
+			"B=${A}",
+		}})},
+	})
+
+	url, err := reader.StringWithError("URL", Expand(true), ForceLowercase(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const expectedURL = "http://Example.com"
+	if url != expectedURL {
+		t.Errorf("expected %q, got %q", expectedURL, url)
+	}
+
+	value, err := reader.StringWithError("A", Expand(true))
+	if !errors.Is(err, ErrExpansionCycle) {
+		t.Errorf("expected error %v to wrap %v", err, ErrExpansionCycle)
+	}
+	const expectedErrMessage = "environment variable A: expanding value: " +
+		"variable references form a cycle: A -> B -> A"
+	if err != nil && err.Error() != expectedErrMessage {
+		t.Errorf("expected error message %q, got %q", expectedErrMessage, err.Error())
+	}
+	if value != "${b}" {
+		t.Errorf("expected unexpanded value %q, got %q", "${b}", value)
+	}
+}
+
+func Test_Reader_secretsdirFileTooLarge(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+	err := os.WriteFile(filepath.Join(directory, "token"), []byte("0123456789"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader := New(Settings{
+		Sources: []Source{secretsdir.New(secretsdir.Settings{
+			Path:        directory,
+			MaxFileSize: 9,
+		})},
+	})
+
+	token, err := reader.StringWithError("TOKEN")
+
+	if token != "" {
+		t.Errorf("expected empty token, got %q", token)
+	}
+	if !errors.Is(err, secretsdir.ErrFileTooLarge) {
+		t.Errorf("expected error %v to wrap %v", err, secretsdir.ErrFileTooLarge)
+	}
+}
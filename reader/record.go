@@ -0,0 +1,154 @@
+package reader
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/qdm12/gosettings"
+	"github.com/qdm12/gosettings/internal/parse"
+)
+
+// RecordEntry is the record of a key queried on the reader.
+type RecordEntry struct {
+	// Key is the key queried, as given to the reader.
+	Key string `json:"key"`
+	// IsSet is true if the key is set in one of the sources.
+	IsSet bool `json:"set"`
+	// Value is the value after processing, obfuscated using
+	// gosettings.ObfuscateKey if the Secret option was used.
+	// It is empty if the key is not set.
+	Value string `json:"value"`
+	// Secret is true if the value is obfuscated.
+	Secret bool `json:"secret,omitempty"`
+	// Source is the name of the source the value was found in,
+	// for example 'environment variable'.
+	Source string `json:"source,omitempty"`
+	// SourceKey is the key the value was found at, as
+	// transformed by the source.
+	SourceKey string `json:"source_key,omitempty"`
+	// Location is the location of the key in the source,
+	// for example 'line 5'.
+	Location string `json:"location,omitempty"`
+	// Deprecated is true if the value was found at a
+	// deprecated key instead of the current key.
+	Deprecated bool `json:"deprecated,omitempty"`
+}
+
+// Record is the record of keys queried on the reader,
+// in the order they were first queried.
+type Record []RecordEntry
+
+// Record returns the record of all keys queried so far, in the
+// order they were first queried. If a key is queried more than
+// once, its entry reflects the last query.
+// It returns nil if the reader Record setting is not enabled.
+func (r *Reader) Record() (record Record) {
+	if r.record == nil {
+		return nil
+	}
+	return r.record.entries()
+}
+
+// String returns the record as a text table with the
+// columns KEY, VALUE and ORIGIN.
+func (r Record) String() string {
+	var builder strings.Builder
+	const minWidth, tabWidth, padding = 0, 0, 2
+	writer := tabwriter.NewWriter(&builder, minWidth, tabWidth, padding, ' ', 0)
+	_, _ = writer.Write([]byte("KEY\tVALUE\tORIGIN\n"))
+	for _, entry := range r {
+		value := entry.Value
+		if !entry.IsSet {
+			value = "[not set]"
+		}
+		line := entry.Key + "\t" + value
+		if entry.IsSet {
+			line += "\t" + entry.origin()
+		}
+		_, _ = writer.Write([]byte(line + "\n"))
+	}
+	_ = writer.Flush()
+	return builder.String()
+}
+
+func (r RecordEntry) origin() string {
+	origin := r.Source + " " + r.SourceKey
+	if r.Location != "" {
+		origin += " (" + r.Location + ")"
+	}
+	if r.Deprecated {
+		origin += " [deprecated]"
+	}
+	return origin
+}
+
+// JSON returns the record encoded as an indented JSON array.
+func (r Record) JSON() (data []byte, err error) {
+	if r == nil {
+		r = Record{}
+	}
+	return json.MarshalIndent(r, "", "  ")
+}
+
+type record struct {
+	mutex      sync.Mutex
+	keys       []string
+	keyToEntry map[string]RecordEntry
+	// secretKeys contains the keys queried at least once
+	// with the Secret option, whose values are then always
+	// obfuscated.
+	secretKeys map[string]struct{}
+}
+
+func newRecord() *record {
+	return &record{
+		keyToEntry: make(map[string]RecordEntry),
+		secretKeys: make(map[string]struct{}),
+	}
+}
+
+// add records the key and its lookup result. Once a key is
+// recorded as secret, its value is obfuscated for all its
+// later queries, even if they do not use the Secret option.
+func (r *record) add(key string, result parse.LookupResult, secret bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if secret {
+		r.secretKeys[key] = struct{}{}
+	} else {
+		_, secret = r.secretKeys[key]
+	}
+
+	entry := RecordEntry{
+		Key:        key,
+		IsSet:      result.IsSet,
+		Value:      result.Value,
+		Source:     result.Source,
+		SourceKey:  result.Key,
+		Location:   result.Location,
+		Deprecated: result.Deprecated,
+	}
+	if secret && result.IsSet {
+		entry.Value = gosettings.ObfuscateKey(result.Value)
+		entry.Secret = true
+	}
+
+	_, exists := r.keyToEntry[key]
+	if !exists {
+		r.keys = append(r.keys, key)
+	}
+	r.keyToEntry[key] = entry
+}
+
+func (r *record) entries() (entries Record) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	entries = make(Record, len(r.keys))
+	for i, key := range r.keys {
+		entries[i] = r.keyToEntry[key]
+	}
+	return entries
+}
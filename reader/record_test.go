@@ -0,0 +1,140 @@
+package reader
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/qdm12/gosettings/reader/sources/flag"
+)
+
+func Test_Reader_Record(t *testing.T) {
+	t.Parallel()
+
+	source := &testSource{keyValue: map[string]string{
+		"PORT":     "8000",
+		"OLD_NAME": "Name",
+		"PASSWORD": "0123456789abcdefghijklmnopqrstuvwxyz",
+	}}
+	reader := New(Settings{
+		Sources: []Source{source},
+		Record:  true,
+	})
+
+	_, err := reader.Uint16("PORT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = reader.String("NAME", RetroKeys("OLD_NAME"))
+	_ = reader.String("PASSWORD", Secret(), ForceLowercase(false))
+	_ = reader.String("MISSING")
+	_ = reader.String("PORT") // already recorded
+
+	record := reader.Record()
+
+	expectedRecord := Record{
+		{Key: "PORT", IsSet: true, Value: "8000", Source: "test", SourceKey: "PORT"},
+		{
+			Key: "NAME", IsSet: true, Value: "name", Source: "test",
+			SourceKey: "OLD_NAME", Deprecated: true,
+		},
+		{
+			Key: "PASSWORD", IsSet: true, Value: "012...xyz", Secret: true,
+			Source: "test", SourceKey: "PASSWORD",
+		},
+		{Key: "MISSING"},
+	}
+	if !reflect.DeepEqual(record, expectedRecord) {
+		t.Fatalf("expected record %#v, got %#v", expectedRecord, record)
+	}
+
+	const expectedString = "KEY       VALUE      ORIGIN\n" +
+		"PORT      8000       test PORT\n" +
+		"NAME      name       test OLD_NAME [deprecated]\n" +
+		"PASSWORD  012...xyz  test PASSWORD\n" +
+		"MISSING   [not set]\n"
+	if record.String() != expectedString {
+		t.Errorf("expected string:\n%s\ngot:\n%s", expectedString, record.String())
+	}
+
+	data, err := Record{record[0], record[3]}.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	const expectedJSON = `[
+  {
+    "key": "PORT",
+    "set": true,
+    "value": "8000",
+    "source": "test",
+    "source_key": "PORT"
+  },
+  {
+    "key": "MISSING",
+    "set": false,
+    "value": ""
+  }
+]`
+	if string(data) != expectedJSON {
+		t.Errorf("expected JSON:\n%s\ngot:\n%s", expectedJSON, string(data))
+	}
+}
+
+func Test_Reader_Record_secretKept(t *testing.T) {
+	t.Parallel()
+
+	source := &testSource{keyValue: map[string]string{
+		"PASSWORD": "0123456789abcdefghijklmnopqrstuvwxyz",
+	}}
+	reader := New(Settings{
+		Sources: []Source{source},
+		Record:  true,
+	})
+
+	_ = reader.String("PASSWORD", Secret(), ForceLowercase(false))
+	_ = reader.String("PASSWORD", ForceLowercase(false))
+
+	record := reader.Record()
+
+	expectedRecord := Record{{
+		Key: "PASSWORD", IsSet: true, Value: "012...xyz", Secret: true,
+		Source: "test", SourceKey: "PASSWORD",
+	}}
+	if !reflect.DeepEqual(record, expectedRecord) {
+		t.Errorf("expected record %#v, got %#v", expectedRecord, record)
+	}
+}
+
+func Test_Reader_Record_disabled(t *testing.T) {
+	t.Parallel()
+
+	reader := New(Settings{Sources: []Source{&testSource{}}})
+	_ = reader.String("KEY")
+
+	record := reader.Record()
+	if record != nil {
+		t.Errorf("expected nil record, got %#v", record)
+	}
+}
+
+func Test_Reader_Record_repeatedFlags(t *testing.T) {
+	t.Parallel()
+
+	reader := New(Settings{
+		Sources: []Source{flag.New([]string{"program",
+			"--header", "a,b", "--header=c", "--h", "c", "--h", ""})},
+		Record: true,
+	})
+
+	_ = reader.CSV("HEADER")
+	_ = reader.CSV("H")
+
+	record := reader.Record()
+
+	expectedRecord := Record{
+		{Key: "HEADER", IsSet: true, Value: "a,b,c", Source: "flag", SourceKey: "header"},
+		{Key: "H", IsSet: true, Value: "c", Source: "flag", SourceKey: "h"},
+	}
+	if !reflect.DeepEqual(record, expectedRecord) {
+		t.Errorf("expected record %#v, got %#v", expectedRecord, record)
+	}
+}
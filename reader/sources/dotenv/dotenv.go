@@ -0,0 +1,89 @@
+package dotenv
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/exp/maps"
+)
+
+// Source implements a dotenv file settings source.
+// Note all keys are transformed using its KeyTransform
+// method, which behaves like the environment variable
+// source KeyTransform method.
+type Source struct {
+	path       string
+	keyToValue map[string]string
+	keyPrefix  string
+}
+
+// New creates a new dotenv file source by reading and
+// parsing the dotenv file at the path given in settings.
+// The file format supported is:
+//   - empty lines and lines starting with `#` are ignored
+//   - an optional `export ` prefix before the key
+//   - unquoted values, where ` #` starts an inline comment
+//   - single quoted values, which are taken literally
+//   - double quoted values, which can span multiple lines and
+//     support the escape sequences \n, \r, \t, \", \\ and \$
+//
+// An error is returned if the file cannot be read or parsed,
+// with the file path and line number in its message.
+func New(settings Settings) (source *Source, err error) {
+	settings.setDefaults()
+
+	content, err := os.ReadFile(settings.Path)
+	if err != nil {
+		return nil, fmt.Errorf("reading dotenv file: %w", err)
+	}
+
+	keyToValue, err := parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("parsing dotenv file %s: %w", settings.Path, err)
+	}
+
+	source = &Source{
+		path:       settings.Path,
+		keyToValue: make(map[string]string, len(keyToValue)),
+		keyPrefix:  settings.KeyPrefix,
+	}
+	for key, value := range keyToValue {
+		key = normalizeKey(key)
+		source.keyToValue[key] = value
+	}
+
+	return source, nil
+}
+
+func (s *Source) String() string {
+	return "dotenv file " + s.path
+}
+
+// Get returns the value of the dotenv variable
+// found at the given key, and a boolean `isSet` to
+// indicate if it is set or not.
+func (s *Source) Get(key string) (value string, isSet bool) {
+	value, isSet = s.keyToValue[key]
+	return value, isSet
+}
+
+// KeyTransform transforms a generic key to a dotenv
+// variable key. It notably:
+// - Changes all characters to be uppercase
+// - Replaces all dashes with underscores.
+// - Prefixes the key with the KeyPrefix field, without modifying the prefix.
+func (s *Source) KeyTransform(key string) (newKey string) {
+	return s.keyPrefix + normalizeKey(key)
+}
+
+// Keys returns all the keys set in the dotenv file.
+func (s *Source) Keys() (keys []string) {
+	return maps.Keys(s.keyToValue)
+}
+
+func normalizeKey(key string) (newKey string) {
+	newKey = strings.ToUpper(key)
+	newKey = strings.ReplaceAll(newKey, "-", "_")
+	return newKey
+}
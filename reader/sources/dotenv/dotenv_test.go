@@ -0,0 +1,246 @@
+package dotenv
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		content    string
+		settings   Settings
+		source     *Source
+		errWrapped error
+		errRegex   *regexp.Regexp
+	}{
+		"empty_file": {
+			source: &Source{
+				keyToValue: map[string]string{},
+			},
+		},
+		"keys_transformed": {
+			content:  "my-key=value\nPREFIX_KEY=value2",
+			settings: Settings{KeyPrefix: "PREFIX_"},
+			source: &Source{
+				keyToValue: map[string]string{
+					"MY_KEY":     "value",
+					"PREFIX_KEY": "value2",
+				},
+				keyPrefix: "PREFIX_",
+			},
+		},
+		"parse_error": {
+			content:    "KEY=value\n\nKEY2",
+			errWrapped: ErrEqualSignMissing,
+			errRegex: regexp.MustCompile(`^parsing dotenv file /.+/\.env: ` +
+				`line 3: equal sign is missing: KEY2$`),
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), ".env")
+			err := os.WriteFile(path, []byte(testCase.content), 0600)
+			if err != nil {
+				t.Fatal(err)
+			}
+			settings := testCase.settings
+			settings.Path = path
+
+			source, err := New(settings)
+
+			if !errors.Is(err, testCase.errWrapped) {
+				t.Errorf("expected error %v to wrap %v", err, testCase.errWrapped)
+			}
+			if testCase.errWrapped != nil {
+				if !testCase.errRegex.MatchString(err.Error()) {
+					t.Errorf("expected error message to match %s, got %s",
+						testCase.errRegex, err)
+				}
+				return
+			}
+			testCase.source.path = path
+			if !reflect.DeepEqual(source, testCase.source) {
+				t.Errorf("expected source %#v, got %#v", testCase.source, source)
+			}
+		})
+	}
+
+	t.Run("file_not_found", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), ".env")
+		_, err := New(Settings{Path: path})
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected error %v to wrap %v", err, os.ErrNotExist)
+		}
+	})
+}
+
+func Test_parse(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		content    string
+		keyToValue map[string]string
+		errWrapped error
+		errMessage string
+	}{
+		"empty": {
+			keyToValue: map[string]string{},
+		},
+		"comments_and_empty_lines": {
+			content: "# comment\n\n   \n  # indented comment\nKEY=value\n",
+			keyToValue: map[string]string{
+				"KEY": "value",
+			},
+		},
+		"export_prefix": {
+			content: "export KEY=value\nexport\tKEY2=value2\nexporter=value3",
+			keyToValue: map[string]string{
+				"KEY":      "value",
+				"KEY2":     "value2",
+				"exporter": "value3",
+			},
+		},
+		"unquoted_values": {
+			content: "A=\nB = spaced value  \nC=value # comment\nD=value#not-comment\r\nE=a=b",
+			keyToValue: map[string]string{
+				"A": "",
+				"B": "spaced value",
+				"C": "value",
+				"D": "value#not-comment",
+				"E": "a=b",
+			},
+		},
+		"single_quoted_values": {
+			content: `A='  literal \n ${X} "quoted" '` + "\n" +
+				`B='value' # comment`,
+			keyToValue: map[string]string{
+				"A": `  literal \n ${X} "quoted" `,
+				"B": "value",
+			},
+		},
+		"double_quoted_values": {
+			content: `A="tab\there \"quoted\" \\ \$HOME \q"` + "\n" +
+				`B="value" # comment`,
+			keyToValue: map[string]string{
+				"A": "tab\there \"quoted\" \\ $HOME \\q",
+				"B": "value",
+			},
+		},
+		"multi_line_double_quoted_value": {
+			content: "A=\"first line  \n  second line\n\nfourth line\"\nB=b",
+			keyToValue: map[string]string{
+				"A": "first line  \n  second line\n\nfourth line",
+				"B": "b",
+			},
+		},
+		"last_value_wins": {
+			content: "KEY=a\nKEY=b",
+			keyToValue: map[string]string{
+				"KEY": "b",
+			},
+		},
+		"equal_sign_missing": {
+			content:    "A=a\nB",
+			errWrapped: ErrEqualSignMissing,
+			errMessage: "line 2: equal sign is missing: B",
+		},
+		"key_empty": {
+			content:    "=value",
+			errWrapped: ErrKeyNotValid,
+			errMessage: `line 1: key is not valid: ""`,
+		},
+		"key_starting_with_digit": {
+			content:    "\n1KEY=value",
+			errWrapped: ErrKeyNotValid,
+			errMessage: `line 2: key is not valid: "1KEY"`,
+		},
+		"single_quote_not_closed": {
+			content:    "KEY='value\nOTHER='x'",
+			errWrapped: ErrQuoteNotClosed,
+			errMessage: "line 1: quote is not closed: 'value",
+		},
+		"double_quote_not_closed": {
+			content:    "A=a\nKEY=\"value\nOTHER=x",
+			errWrapped: ErrQuoteNotClosed,
+			errMessage: `line 2: quote is not closed: "value`,
+		},
+		"characters_after_closing_quote": {
+			content:    `KEY="value" other`,
+			errWrapped: ErrTrailingCharacters,
+			errMessage: "line 1: unexpected characters after closing quote: other",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			keyToValue, err := parse(testCase.content)
+
+			if !errors.Is(err, testCase.errWrapped) {
+				t.Errorf("expected error %v to wrap %v", err, testCase.errWrapped)
+			}
+			if testCase.errWrapped != nil {
+				if err.Error() != testCase.errMessage {
+					t.Errorf("expected error message %q, got %q",
+						testCase.errMessage, err.Error())
+				}
+				return
+			}
+			if !reflect.DeepEqual(keyToValue, testCase.keyToValue) {
+				t.Errorf("expected %#v, got %#v", testCase.keyToValue, keyToValue)
+			}
+		})
+	}
+}
+
+func Test_Source_KeyTransform(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		keyPrefix string
+		key       string
+		newKey    string
+	}{
+		"empty": {},
+		"no_change": {
+			key:    "ENV_KEY",
+			newKey: "ENV_KEY",
+		},
+		"flag_like": {
+			key:    "env-key",
+			newKey: "ENV_KEY",
+		},
+		"with_prefix": {
+			keyPrefix: "Prefix_",
+			key:       "env-key",
+			newKey:    "Prefix_ENV_KEY",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			source := &Source{keyPrefix: testCase.keyPrefix}
+			newKey := source.KeyTransform(testCase.key)
+			if newKey != testCase.newKey {
+				t.Errorf("expected %s, got %s", testCase.newKey, newKey)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package dotenv
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrEqualSignMissing   = errors.New("equal sign is missing")
+	ErrKeyNotValid        = errors.New("key is not valid")
+	ErrQuoteNotClosed     = errors.New("quote is not closed")
+	ErrTrailingCharacters = errors.New("unexpected characters after closing quote")
+)
+
+func parse(content string) (keyToValue map[string]string, err error) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	lines := strings.Split(content, "\n")
+	keyToValue = make(map[string]string, len(lines))
+
+	for i := 0; i < len(lines); i++ {
+		lineNumber := i + 1
+		line := strings.TrimLeft(lines[i], " \t")
+		if strings.TrimSpace(line) == "" || line[0] == '#' {
+			continue
+		}
+
+		key, rest, err := parseKey(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		value, extraLines, err := parseValue(rest, lines[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		i += extraLines
+		keyToValue[key] = value
+	}
+
+	return keyToValue, nil
+}
+
+// parseKey parses the key from the line given, and returns
+// the rest of the line after the equal sign, with its leading
+// spaces trimmed.
+func parseKey(line string) (key, rest string, err error) {
+	const exportPrefix = "export"
+	afterExport, hasExport := strings.CutPrefix(line, exportPrefix)
+	if hasExport && afterExport != "" &&
+		(afterExport[0] == ' ' || afterExport[0] == '\t') {
+		line = strings.TrimLeft(afterExport, " \t")
+	}
+
+	key, rest, found := strings.Cut(line, "=")
+	if !found {
+		return "", "", fmt.Errorf("%w: %s", ErrEqualSignMissing,
+			strings.TrimSpace(line))
+	}
+
+	key = strings.TrimRight(key, " \t")
+	if !isKeyValid(key) {
+		return "", "", fmt.Errorf("%w: %q", ErrKeyNotValid, key)
+	}
+
+	rest = strings.TrimLeft(rest, " \t")
+	return key, rest, nil
+}
+
+// isKeyValid returns true if the key is not empty, starts
+// with a letter or an underscore, and only contains letters,
+// digits, underscores, dots and dashes.
+func isKeyValid(key string) (valid bool) {
+	if key == "" {
+		return false
+	}
+	for i, r := range key {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+		case i > 0 && (r >= '0' && r <= '9' || r == '.' || r == '-'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// parseValue parses the value from the given string found after
+// the equal sign, and uses the next lines given if the value is
+// a multi-line double quoted value. It returns the number of
+// extra lines used from the next lines given.
+func parseValue(s string, nextLines []string) (value string,
+	extraLines int, err error) {
+	switch {
+	case s == "":
+		return "", 0, nil
+	case s[0] == '\'':
+		closingIndex := strings.IndexByte(s[1:], '\'')
+		if closingIndex == -1 {
+			return "", 0, fmt.Errorf("%w: %s", ErrQuoteNotClosed, s)
+		}
+		value = s[1 : 1+closingIndex]
+		err = checkAfterQuote(s[2+closingIndex:])
+		return value, 0, err
+	case s[0] == '"':
+		return parseDoubleQuoted(s[1:], nextLines)
+	default:
+		return parseUnquoted(s), 0, nil
+	}
+}
+
+// parseUnquoted returns the value from the string given,
+// removing any inline comment starting with a space or tab
+// followed by a `#`, and trimming surrounding spaces.
+func parseUnquoted(s string) (value string) {
+	for i := 1; i < len(s); i++ {
+		if s[i] == '#' && (s[i-1] == ' ' || s[i-1] == '\t') {
+			s = s[:i]
+			break
+		}
+	}
+	return strings.TrimSpace(s)
+}
+
+// parseDoubleQuoted parses a double quoted value starting
+// after its opening double quote, using the next lines given
+// if the value spans multiple lines.
+func parseDoubleQuoted(s string, nextLines []string) (value string,
+	extraLines int, err error) {
+	firstLine := s
+	var builder strings.Builder
+	for {
+		for i := 0; i < len(s); i++ {
+			switch s[i] {
+			case '"':
+				err = checkAfterQuote(s[i+1:])
+				return builder.String(), extraLines, err
+			case '\\':
+				if i == len(s)-1 {
+					builder.WriteByte('\\')
+					continue
+				}
+				i++
+				builder.WriteString(unescape(s[i]))
+			default:
+				builder.WriteByte(s[i])
+			}
+		}
+
+		if extraLines == len(nextLines) {
+			return "", 0, fmt.Errorf("%w: \"%s", ErrQuoteNotClosed, firstLine)
+		}
+		builder.WriteByte('\n')
+		s = nextLines[extraLines]
+		extraLines++
+	}
+}
+
+func unescape(b byte) (s string) {
+	switch b {
+	case 'n':
+		return "\n"
+	case 'r':
+		return "\r"
+	case 't':
+		return "\t"
+	case '"', '\\', '$':
+		return string(b)
+	default:
+		return "\\" + string(b)
+	}
+}
+
+// checkAfterQuote verifies the string found after a closing
+// quote only contains spaces and an eventual comment.
+func checkAfterQuote(s string) (err error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s[0] == '#' {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrTrailingCharacters, s)
+}
@@ -0,0 +1,22 @@
+package dotenv
+
+import (
+	"github.com/qdm12/gosettings"
+)
+
+// Settings contains settings for the dotenv file source.
+type Settings struct {
+	// Path is the path to the dotenv file to read.
+	// It defaults to ".env".
+	Path string
+	// KeyPrefix is a prefix to add to all keys read from
+	// the dotenv file, in the same way as the environment
+	// variable source KeyPrefix field. Keys in the dotenv
+	// file are therefore expected to start with this prefix.
+	// It defaults to the empty string.
+	KeyPrefix string
+}
+
+func (s *Settings) setDefaults() {
+	s.Path = gosettings.DefaultComparable(s.Path, ".env")
+}
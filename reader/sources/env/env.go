@@ -1,28 +1,50 @@
 package env
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
 	"strings"
+	"sync"
 )
 
 // Source implements an environment variables source.
 // Note all keys are transformed using its KeyTransform
 // method.
 type Source struct {
-	keyToValue map[string]string
-	keyPrefix  string
+	keyToValue  map[string]string
+	keyPrefix   string
+	fileSuffix  string
+	maxFileSize int64
+	handleError func(err error)
+
+	fileKeysMutex sync.Mutex
+	// usedFileKeys contains the keys with the file suffix
+	// used to read the value of their key without the suffix.
+	usedFileKeys map[string]struct{}
 }
 
 // New creates a new environment variable source
 // given the environment as a slice of key-value pairs,
 // which can generally be obtained with os.Environ().
 // Environ values with no '=' sign are ignored.
-// All environment variable keys read are eventually
-// transformed using the KeyTransform method.
+// Environment variable keys read are normalized like keys
+// transformed by the KeyTransform method, but are not prefixed
+// with the KeyPrefix settings field since they are expected to
+// already contain it. For example with the prefix APP_, the key
+// KEY is read from the environment variable APP_KEY.
+// If the FileSuffix settings field is set, values can be
+// read from files, see the Settings FileSuffix field.
 func New(settings Settings) (source *Source) {
 	settings.setDefaults()
 	source = &Source{
-		keyToValue: make(map[string]string, len(settings.Environ)),
-		keyPrefix:  settings.KeyPrefix,
+		keyToValue:  make(map[string]string, len(settings.Environ)),
+		keyPrefix:   settings.KeyPrefix,
+		fileSuffix:  normalizeKey(settings.FileSuffix),
+		maxFileSize: settings.MaxFileSize,
+		handleError: settings.HandleError,
 	}
 
 	for _, keyValue := range settings.Environ {
@@ -32,7 +54,9 @@ func New(settings Settings) (source *Source) {
 			continue
 		}
 
-		key := source.KeyTransform(parts[0])
+		// Note the environment variable key already contains
+		// the key prefix, so it is only normalized.
+		key := normalizeKey(parts[0])
 		value := parts[1]
 		source.keyToValue[key] = value
 	}
@@ -47,19 +71,145 @@ func (s *Source) String() string {
 // Get returns the value of the environment variable
 // found at the given key, and a boolean `isSet` to
 // indicate if it is set or not.
+// Errors reading the value from a file, see GetWithError,
+// are reported using the HandleError settings field, and
+// the key is then considered as not set.
 func (s *Source) Get(key string) (value string, isSet bool) {
-	value, isSet = s.keyToValue[key]
+	value, isSet, err := s.GetWithError(key)
+	if err != nil {
+		s.handleError(err)
+		return "", false
+	}
 	return value, isSet
 }
 
+// GetWithError returns the value of the environment variable
+// found at the given key, and a boolean `isSet` to indicate
+// if it is set or not.
+// If the source has a file suffix set and the key is not
+// set, the value is read from the file at the path given
+// by the key suffixed with the file suffix. An error is
+// returned if both the key and the key with the file suffix
+// are set, if the file cannot be read or if it is larger than
+// the MaxFileSize settings field.
+func (s *Source) GetWithError(key string) (value string, isSet bool, err error) {
+	value, isSet = s.keyToValue[key]
+	if s.fileSuffix == "" {
+		return value, isSet, nil
+	}
+
+	fileKey := key + s.fileSuffix
+	path := s.keyToValue[fileKey]
+	if path == "" {
+		return value, isSet, nil
+	}
+
+	s.fileKeysMutex.Lock()
+	if s.usedFileKeys == nil {
+		s.usedFileKeys = make(map[string]struct{})
+	}
+	s.usedFileKeys[fileKey] = struct{}{}
+	s.fileKeysMutex.Unlock()
+
+	if isSet {
+		return "", false, fmt.Errorf("%w: %s and %s",
+			ErrValueAndFileSet, key, fileKey)
+	}
+
+	value, err = s.readFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("reading file for %s: %w", fileKey, err)
+	}
+	return value, true, nil
+}
+
+var ErrFileTooLarge = errors.New("file is too large")
+
+func (s *Source) readFile(path string) (content string, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, s.maxFileSize+1))
+	if err != nil {
+		return "", err
+	}
+	if int64(len(data)) > s.maxFileSize {
+		return "", fmt.Errorf("%w: %s exceeds %d bytes",
+			ErrFileTooLarge, path, s.maxFileSize)
+	}
+	return string(data), nil
+}
+
+var ErrValueAndFileSet = errors.New("both value and file are set")
+
+// Locate returns the file path the value of the given key
+// is read from, in the form `file /run/secrets/db`, if the
+// source has a file suffix set and the key is not set but
+// the key with the file suffix is set.
+// It returns the empty string otherwise.
+func (s *Source) Locate(key string) (location string) {
+	if s.fileSuffix == "" {
+		return ""
+	}
+	_, isSet := s.keyToValue[key]
+	path := s.keyToValue[key+s.fileSuffix]
+	if isSet || path == "" {
+		return ""
+	}
+	return "file " + path
+}
+
 // KeyTransform transforms a generic key to an environment
 // variable key. It notably:
 // - Changes all characters to be uppercase
 // - Replaces all dashes with underscores.
 // - Prefixes the key with the KeyPrefix field, without modifying the prefix.
 func (s *Source) KeyTransform(key string) (newKey string) {
+	return s.keyPrefix + normalizeKey(key)
+}
+
+// FormatKey returns the environment variable form of the key
+// given, to be shown in usage text, for example `PREFIX_KEY`.
+func (s *Source) FormatKey(key string) (formatted string) {
+	return s.KeyTransform(key)
+}
+
+func normalizeKey(key string) (newKey string) {
 	newKey = strings.ToUpper(key)
 	newKey = strings.ReplaceAll(newKey, "-", "_")
-	newKey = s.keyPrefix + newKey
 	return newKey
 }
+
+// Keys returns all the environment variable keys starting with the
+// source key prefix. It returns nil if the key prefix is empty, since
+// the environment then cannot be told apart from variables set for
+// other programs, such as HOME or PATH.
+// If the source has a file suffix set, keys ending with the file
+// suffix which were used to read the value of their key without the
+// suffix are returned without the file suffix, such that they are
+// not reported as unused. Other keys ending with the file suffix,
+// such as a setting named CONFIG_FILE, are returned as is.
+func (s *Source) Keys() (keys []string) {
+	if s.keyPrefix == "" {
+		return nil
+	}
+
+	s.fileKeysMutex.Lock()
+	defer s.fileKeysMutex.Unlock()
+	keys = make([]string, 0, len(s.keyToValue))
+	for key := range s.keyToValue {
+		if !strings.HasPrefix(key, s.keyPrefix) {
+			continue
+		}
+		_, usedAsFile := s.usedFileKeys[key]
+		if usedAsFile {
+			key = strings.TrimSuffix(key, s.fileSuffix)
+		}
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return slices.Compact(keys)
+}
@@ -1,8 +1,10 @@
 package env
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -82,6 +84,12 @@ func Test_Env_Get(t *testing.T) {
 			key:   "KEY",
 			isSet: true,
 		},
+		"key_prefix": {
+			env:   New(Settings{Environ: []string{"APP_KEY=value"}, KeyPrefix: "APP_"}),
+			key:   "APP_KEY",
+			value: "value",
+			isSet: true,
+		},
 		"non_empty_value": {
 			env:   New(Settings{Environ: []string{"KEY=value"}}),
 			key:   "KEY",
@@ -107,6 +115,36 @@ func Test_Env_Get(t *testing.T) {
 	}
 }
 
+func Test_Env_keyPrefix(t *testing.T) {
+	t.Parallel()
+
+	env := New(Settings{
+		Environ:   []string{"APP_KEY=prefixed", "KEY=unprefixed", "app-other=value"},
+		KeyPrefix: "APP_",
+	})
+
+	expectedKeyToValue := map[string]string{
+		"APP_KEY":   "prefixed",
+		"KEY":       "unprefixed",
+		"APP_OTHER": "value",
+	}
+	if !reflect.DeepEqual(env.keyToValue, expectedKeyToValue) {
+		t.Errorf("expected %v, got %v", expectedKeyToValue, env.keyToValue)
+	}
+
+	testCases := map[string]string{
+		"key":   "prefixed",
+		"other": "value",
+	}
+	for key, expectedValue := range testCases {
+		value, isSet := env.Get(env.KeyTransform(key))
+		if !isSet || value != expectedValue {
+			t.Errorf("for key %s: expected value %q, got %q (set %t)",
+				key, expectedValue, value, isSet)
+		}
+	}
+}
+
 func Test_Env_KeyTransform(t *testing.T) {
 	t.Parallel()
 
@@ -173,3 +211,195 @@ func setTestEnv(t *testing.T, keyPrefix, value string) (key string) {
 	}
 	return key
 }
+
+func Test_Env_Get_file(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "secret")
+	err := os.WriteFile(path, []byte("secret\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	missingPath := filepath.Join(t.TempDir(), "missing")
+
+	testCases := map[string]struct {
+		environ     []string
+		fileSuffix  string
+		maxFileSize int64
+		key         string
+		value       string
+		isSet       bool
+		location    string
+		errWrapped  error
+	}{
+		"file_suffix_disabled": {
+			environ: []string{"KEY_FILE=" + path},
+			key:     "KEY",
+		},
+		"value_set": {
+			environ:    []string{"KEY=value"},
+			fileSuffix: "_FILE",
+			key:        "KEY",
+			value:      "value",
+			isSet:      true,
+		},
+		"file_set": {
+			environ:    []string{"KEY_FILE=" + path},
+			fileSuffix: "_FILE",
+			key:        "KEY",
+			value:      "secret\n",
+			isSet:      true,
+			location:   "file " + path,
+		},
+		"lowercase_file_suffix": {
+			environ:    []string{"KEY_FILE=" + path},
+			fileSuffix: "_file",
+			key:        "KEY",
+			value:      "secret\n",
+			isSet:      true,
+			location:   "file " + path,
+		},
+		"file_too_large": {
+			environ:     []string{"KEY_FILE=" + path},
+			fileSuffix:  "_FILE",
+			maxFileSize: 6,
+			key:         "KEY",
+			location:    "file " + path,
+			errWrapped:  ErrFileTooLarge,
+		},
+		"empty_file_path": {
+			environ:    []string{"KEY_FILE="},
+			fileSuffix: "_FILE",
+			key:        "KEY",
+		},
+		"value_and_file_set": {
+			environ:    []string{"KEY=value", "KEY_FILE=" + path},
+			fileSuffix: "_FILE",
+			key:        "KEY",
+			errWrapped: ErrValueAndFileSet,
+		},
+		"file_not_found": {
+			environ:    []string{"KEY_FILE=" + missingPath},
+			fileSuffix: "_FILE",
+			key:        "KEY",
+			location:   "file " + missingPath,
+			errWrapped: os.ErrNotExist,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var handledErr error
+			env := New(Settings{
+				Environ:     testCase.environ,
+				FileSuffix:  testCase.fileSuffix,
+				MaxFileSize: testCase.maxFileSize,
+				HandleError: func(err error) {
+					handledErr = err
+				},
+			})
+
+			value, isSet := env.Get(testCase.key)
+			location := env.Locate(testCase.key)
+
+			if value != testCase.value {
+				t.Errorf("expected value %q, got %q", testCase.value, value)
+			}
+			if isSet != testCase.isSet {
+				t.Errorf("expected isSet %t, got %t", testCase.isSet, isSet)
+			}
+			if location != testCase.location {
+				t.Errorf("expected location %q, got %q", testCase.location, location)
+			}
+			if !errors.Is(handledErr, testCase.errWrapped) {
+				t.Errorf("expected error %v to wrap %v", handledErr, testCase.errWrapped)
+			}
+		})
+	}
+}
+
+func Test_Env_Keys(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		settings Settings
+		keys     []string
+	}{
+		"no_prefix": {
+			settings: Settings{Environ: []string{"B=1", "a=2"}},
+		},
+		"key_prefix": {
+			settings: Settings{
+				Environ:   []string{"APP_B=1", "HOME=/root", "APP_A=2"},
+				KeyPrefix: "APP_",
+			},
+			keys: []string{"APP_A", "APP_B"},
+		},
+		"file_suffix_not_used": {
+			settings: Settings{
+				Environ:    []string{"APP_A_FILE=/a", "APP_B=1"},
+				KeyPrefix:  "APP_",
+				FileSuffix: "_FILE",
+			},
+			keys: []string{"APP_A_FILE", "APP_B"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			env := New(testCase.settings)
+
+			keys := env.Keys()
+			if !reflect.DeepEqual(keys, testCase.keys) {
+				t.Errorf("expected keys %v, got %v", testCase.keys, keys)
+			}
+
+			for _, key := range keys {
+				_, isSet := env.Get(key)
+				if !isSet && testCase.settings.FileSuffix == "" {
+					t.Errorf("expected key %s to be set", key)
+				}
+			}
+		})
+	}
+}
+
+func Test_Env_Keys_fileSuffix(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "token")
+	err := os.WriteFile(path, []byte("token"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env := New(Settings{
+		Environ: []string{
+			"APP_TOKEN_FILE=" + path,
+			"APP_CONFIG_FILE=/etc/app.yaml",
+		},
+		KeyPrefix:  "APP_",
+		FileSuffix: "_FILE",
+	})
+
+	_, isSet := env.Get("APP_TOKEN")
+	if !isSet {
+		t.Fatal("expected APP_TOKEN to be set")
+	}
+	value, _ := env.Get("APP_CONFIG_FILE")
+	if value != "/etc/app.yaml" {
+		t.Errorf("expected APP_CONFIG_FILE value %q, got %q", "/etc/app.yaml", value)
+	}
+
+	keys := env.Keys()
+	expectedKeys := []string{"APP_CONFIG_FILE", "APP_TOKEN"}
+	if !reflect.DeepEqual(keys, expectedKeys) {
+		t.Errorf("expected keys %v, got %v", expectedKeys, keys)
+	}
+}
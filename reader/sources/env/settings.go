@@ -11,17 +11,54 @@ type Settings struct {
 	// Environ is a slice of "key=value" pairs which defaults
 	// to the returned value of os.Environ().
 	// Element without a '=' sign are ignored.
-	// All environment variable keys read are eventually
-	// transformed using the KeyTransform method.
+	// Environment variable keys read are normalized like keys
+	// transformed by the KeyTransform method, without adding
+	// the KeyPrefix field.
 	// It defaults to the empty string.
 	Environ []string
-	// KeyPrefix is a prefix to add to all keys read from
-	// the environment. It is usually the program name to avoid
+	// KeyPrefix is a prefix added to all keys transformed by the
+	// KeyTransform method. It is usually the program name to avoid
 	// conflict with other programs in the environment.
+	// Environment variables are therefore expected to start with
+	// this prefix, for example APP_KEY for the key KEY with the
+	// prefix APP_, and only these are listed by the Keys method.
+	// If it is empty, the Keys method lists no key, so environment
+	// variables are never reported by the reader UnusedKeys method.
 	// It defaults to the empty string.
 	KeyPrefix string
+	// FileSuffix is a suffix enabling reading values from files,
+	// following the Docker secrets convention. For example with the
+	// suffix "_FILE", if DB_PASSWORD is not set but DB_PASSWORD_FILE
+	// is set to /run/secrets/db, the value of DB_PASSWORD is read
+	// from the file /run/secrets/db.
+	// The suffix is transformed like keys, so "_file" is
+	// equivalent to "_FILE".
+	// It defaults to the empty string, which disables reading files.
+	FileSuffix string
+	// MaxFileSize is the maximum size in bytes of a file a
+	// value is read from. It is only used if FileSuffix is set,
+	// and defaults to 1MiB.
+	MaxFileSize int64
+	// HandleError is called by the Get method when encountering an
+	// error reading a value from a file, since it cannot return an
+	// error. This happens if both the key and the key with the file
+	// suffix are set, if the file cannot be read or if it is too
+	// large. The key is then considered as not set.
+	// Note the reader uses the GetWithError method instead, so
+	// these errors are returned by its methods returning an error.
+	// It is only used if FileSuffix is set, and defaults to a
+	// no-op function.
+	HandleError func(err error)
 }
 
 func (s *Settings) setDefaults() {
 	s.Environ = gosettings.DefaultSlice(s.Environ, os.Environ())
+	if s.FileSuffix == "" {
+		return
+	}
+	const defaultMaxFileSize = 1 << 20
+	s.MaxFileSize = gosettings.DefaultComparable(s.MaxFileSize, defaultMaxFileSize)
+	if s.HandleError == nil { // Note: cannot use DefaultInterface
+		s.HandleError = func(err error) {}
+	}
 }
@@ -1,14 +1,32 @@
 package flag
 
 import (
+	"slices"
 	"strings"
+
+	"golang.org/x/exp/maps"
 )
 
 // Source implements a CLI flag settings source.
 // Note all keys are transformed using its KeyTransform
 // method.
 type Source struct {
-	keyToValue map[string]string
+	// keyToValues maps each flag key to all the values
+	// given for it, in the order they are given.
+	keyToValues map[string][]string
+	strict      bool
+	// args are the positional arguments, which are
+	// neither flags, flag values nor command names.
+	args []string
+	// remainder are the arguments after the `--` terminator.
+	remainder []string
+	// commands are the names of the commands selected,
+	// for sources created with NewTyped.
+	commands []string
+	// definitions and shortToKey are only set for
+	// sources created with NewTyped.
+	definitions map[string]Definition
+	shortToKey  map[rune]string
 }
 
 // New creates a new flags source from OS arguments.
@@ -16,13 +34,17 @@ type Source struct {
 // a short boolean flag before a command word, for example
 // do not use: ./program --enabled command
 // You can however safely use: ./program --enabled=true command
+// or declare your boolean flags using NewTyped instead.
 // Boolean short flags without an equal sign or a value after
 // have their value set to "true".
+// Arguments which are neither flags nor flag values are available
+// with the Args method, and arguments after `--` are not parsed as
+// flags and are available with the Remainder method.
 // All flag keys read are eventually transformed using
 // the KeyTransform method.
 func New(osArgs []string) (source *Source) {
 	source = &Source{
-		keyToValue: make(map[string]string, len(osArgs)),
+		keyToValues: make(map[string][]string, len(osArgs)),
 	}
 
 	if len(osArgs) > 0 {
@@ -32,17 +54,37 @@ func New(osArgs []string) (source *Source) {
 
 	var key, value string
 	for len(osArgs) > 0 {
+		switch {
+		case osArgs[0] == "--":
+			source.remainder = slices.Clone(osArgs[1:])
+			return source
+		case !isFlag(osArgs[0]):
+			source.args = append(source.args, osArgs[0])
+			osArgs = osArgs[1:]
+			continue
+		}
+
 		key, value, osArgs = parseOne(osArgs)
 		if key == "" {
 			continue
 		}
 		key = source.KeyTransform(key)
-		source.keyToValue[key] = value
+		source.keyToValues[key] = append(source.keyToValues[key], value)
 	}
 
 	return source
 }
 
+// NewStrict creates a new flags source from OS arguments,
+// in the same way as New, except unknown flags are considered
+// as errors by the reader CheckUnusedKeys method. Unknown flags
+// are flags which were never queried on the reader.
+func NewStrict(osArgs []string) (source *Source) {
+	source = New(osArgs)
+	source.strict = true
+	return source
+}
+
 func parseOne(osArgs []string) (key, value string,
 	nextOsArgs []string) {
 	if len(osArgs) == 0 { // this should not happen
@@ -69,7 +111,7 @@ func parseOne(osArgs []string) (key, value string,
 
 	// Special case for true boolean flags
 	valueIsTrue := len(osArgs) == 0 ||
-		isFlag(osArgs[0])
+		isFlag(osArgs[0]) || osArgs[0] == "--"
 	if valueIsTrue {
 		value = "true"
 		return key, value, osArgs
@@ -96,10 +138,22 @@ func (f *Source) String() string {
 
 // Get returns the value of the flag corresponding
 // to the given key, and a boolean `isSet` to
-// indicate if it is set or not.
+// indicate if it is set or not. If the flag is given
+// more than once, its last value is returned.
 func (f *Source) Get(key string) (value string, isSet bool) {
-	value, isSet = f.keyToValue[key]
-	return value, isSet
+	values, isSet := f.keyToValues[key]
+	if !isSet {
+		return "", false
+	}
+	return values[len(values)-1], true
+}
+
+// GetAll returns all the values of the flag corresponding
+// to the given key, in the order they are given, for example
+// [a b] for `--header a --header b`. It returns nil if the
+// flag is not set.
+func (f *Source) GetAll(key string) (values []string) {
+	return slices.Clone(f.keyToValues[key])
 }
 
 // KeyTransform transforms a generic key to a flag
@@ -112,3 +166,49 @@ func (f *Source) KeyTransform(key string) (newKey string) {
 	newKey = strings.ReplaceAll(newKey, " ", "-")
 	return newKey
 }
+
+// FormatKey returns the flag form of the key given, to be shown
+// in usage text, for example `--server-address`, or `-v, --verbose`
+// if the key has a short alias declared with NewTyped.
+func (f *Source) FormatKey(key string) (formatted string) {
+	key = f.KeyTransform(key)
+	formatted = "--" + key
+	definition, ok := f.definitions[key]
+	if ok && definition.Short != 0 {
+		formatted = "-" + string(definition.Short) + ", " + formatted
+	}
+	return formatted
+}
+
+// Keys returns the keys of all the flags set.
+func (f *Source) Keys() (keys []string) {
+	return maps.Keys(f.keyToValues)
+}
+
+// Args returns the positional arguments, which are neither
+// flags, flag values, command names nor after the `--` terminator.
+// For example it returns [a b] for `./program --key=value a b -- c`.
+func (f *Source) Args() (args []string) {
+	return slices.Clone(f.args)
+}
+
+// Remainder returns the arguments after the `--` terminator,
+// which are not parsed as flags. It returns nil if there is
+// no terminator or no argument after it.
+func (f *Source) Remainder() (remainder []string) {
+	return slices.Clone(f.remainder)
+}
+
+// Commands returns the names of the commands selected, from the
+// commands declared in the settings given to NewTyped. For example
+// it returns [migrate up] for `./program migrate --verbose up`.
+// It returns nil if no command is selected.
+func (f *Source) Commands() (commands []string) {
+	return slices.Clone(f.commands)
+}
+
+// Strict returns true if the source was created with NewStrict,
+// in which case unknown flags should be considered as errors.
+func (f *Source) Strict() bool {
+	return f.strict
+}
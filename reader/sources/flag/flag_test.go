@@ -15,37 +15,41 @@ func Test_New(t *testing.T) {
 		"empty": {
 			osArgs: []string{"program"},
 			source: &Source{
-				keyToValue: map[string]string{},
+				keyToValues: map[string][]string{},
 			},
 		},
 		"commands_no_flags": {
 			osArgs: []string{"program", "do-this", "with-that"},
 			source: &Source{
-				keyToValue: map[string]string{},
+				keyToValues: map[string][]string{},
+				args:        []string{"do-this", "with-that"},
 			},
 		},
 		"command_then_flag": {
 			osArgs: []string{"program", "do-this", "-log=yes"},
 			source: &Source{
-				keyToValue: map[string]string{
-					"log": "yes",
+				keyToValues: map[string][]string{
+					"log": {"yes"},
 				},
+				args: []string{"do-this"},
 			},
 		},
 		"flag_then_command": {
 			osArgs: []string{"program", "-log=yes", "do-this"},
 			source: &Source{
-				keyToValue: map[string]string{
-					"log": "yes",
+				keyToValues: map[string][]string{
+					"log": {"yes"},
 				},
+				args: []string{"do-this"},
 			},
 		},
 		"last_flag_override": {
 			osArgs: []string{"program", "-log=yes", "do-this", "-log=no"},
 			source: &Source{
-				keyToValue: map[string]string{
-					"log": "no",
+				keyToValues: map[string][]string{
+					"log": {"yes", "no"},
 				},
+				args: []string{"do-this"},
 			},
 		},
 		"mix": {
@@ -54,12 +58,22 @@ func Test_New(t *testing.T) {
 				"--rpc", "--port", "8000", "not-a-flag",
 			},
 			source: &Source{
-				keyToValue: map[string]string{
-					"log":     "no",
-					"metrics": "prometheus",
-					"rpc":     "true",
-					"port":    "8000",
+				keyToValues: map[string][]string{
+					"log":     {"yes", "no"},
+					"metrics": {"prometheus"},
+					"rpc":     {"true"},
+					"port":    {"8000"},
 				},
+				args: []string{"do-this", "not-a-flag"},
+			},
+		},
+		"terminator": {
+			osArgs: []string{"program", "--rpc", "--", "--log=yes", "do-this"},
+			source: &Source{
+				keyToValues: map[string][]string{
+					"rpc": {"true"},
+				},
+				remainder: []string{"--log=yes", "do-this"},
 			},
 		},
 	}
@@ -127,3 +141,49 @@ func Test_isFlag(t *testing.T) {
 		})
 	}
 }
+
+func Test_Source_Get_GetAll(t *testing.T) {
+	t.Parallel()
+
+	source := New([]string{"program", "--header", "a", "--header=b", "--log=yes"})
+
+	testCases := map[string]struct {
+		key    string
+		value  string
+		isSet  bool
+		values []string
+	}{
+		"not_set": {
+			key: "missing",
+		},
+		"set_once": {
+			key:    "log",
+			value:  "yes",
+			isSet:  true,
+			values: []string{"yes"},
+		},
+		"repeated": {
+			key:    "header",
+			value:  "b",
+			isSet:  true,
+			values: []string{"a", "b"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			value, isSet := source.Get(testCase.key)
+			if value != testCase.value || isSet != testCase.isSet {
+				t.Errorf("expected %q, %t, got %q, %t",
+					testCase.value, testCase.isSet, value, isSet)
+			}
+			values := source.GetAll(testCase.key)
+			if !reflect.DeepEqual(values, testCase.values) {
+				t.Errorf("expected %#v, got %#v", testCase.values, values)
+			}
+		})
+	}
+}
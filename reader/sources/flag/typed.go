@@ -0,0 +1,317 @@
+package flag
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Definition declares a flag for the source created with NewTyped.
+type Definition struct {
+	// Key is the key of the flag, transformed using the source
+	// KeyTransform method, for example VERBOSE for --verbose.
+	Key string
+	// Short is the optional single character alias of the flag,
+	// for example 'v' for -v. It is not set if it is the zero rune.
+	Short rune
+	// Boolean is true if the flag takes no value, such that
+	// `--verbose command` sets the flag to "true" and leaves
+	// `command` as a positional argument. A value can still be
+	// given with an equal sign, for example `--verbose=false`.
+	Boolean bool
+	// Repeatable is true if the flag can be given more than once,
+	// in which case the Get method returns the last value given
+	// and the GetAll method returns all the values given.
+	// Otherwise, giving the flag more than once is an error.
+	Repeatable bool
+}
+
+// Settings contains settings for the source created with NewTyped.
+type Settings struct {
+	// Definitions are the flags declared. Flags not declared
+	// are parsed in the same untyped way as the New function,
+	// unless RejectUnknown is true.
+	Definitions []Definition
+	// RejectUnknown is true to return an error when parsing
+	// a flag not declared in Definitions.
+	RejectUnknown bool
+	// Commands are the commands which can be selected by the
+	// first positional argument, each with their own flags.
+	Commands []Command
+}
+
+// Command declares a command, for example `serve` for
+// `./program serve --port 8000`.
+// Its flags can only be given after its name, and are set
+// in the same source as the global flags, such that a reader
+// using the source reads both, with the fallback to other
+// sources such as environment variables.
+type Command struct {
+	// Name is the name of the command, for example "serve".
+	Name string
+	// Definitions are the flags of the command, in addition to
+	// the flags of its parent commands and the global flags.
+	// Their keys and short aliases cannot be the same as the ones
+	// of these other flags, but can be the same as the ones of
+	// sibling commands.
+	Definitions []Definition
+	// Commands are the sub-commands which can be selected by
+	// the first positional argument after the command name.
+	Commands []Command
+}
+
+var (
+	ErrDefinitionNotValid = errors.New("flag definition is not valid")
+	ErrFlagUnknown        = errors.New("flag is unknown")
+	ErrFlagValueMissing   = errors.New("flag value is missing")
+	ErrFlagRepeated       = errors.New("flag is given more than once")
+)
+
+// NewTyped creates a new flags source from OS arguments, using
+// the flag definitions from the settings given to parse them.
+// Contrary to New:
+//   - boolean flags do not consume the argument following them,
+//     so `./program --enabled command` is parsed correctly
+//   - valued flags always consume the argument following them,
+//     if no value is given with an equal sign
+//   - single dash arguments are parsed as one or more short flags,
+//     such as `-abc` for `-a -b -c` or `-ofile` for `-o file`,
+//     unless their name is the key of a declared flag
+//   - arguments after `--` are not parsed as flags.
+//
+// The first positional argument matching the name of a declared
+// command selects it, which is then available with the Commands
+// method together with its selected sub-commands. Other positional
+// arguments are available with the Args method.
+// All flag keys read are eventually transformed using
+// the KeyTransform method.
+func NewTyped(osArgs []string, settings Settings) (source *Source, err error) {
+	source = newTypedSource()
+	for _, definition := range settings.Definitions {
+		err = source.addDefinition(definition)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = validateCommands(settings.Definitions, settings.Commands)
+	if err != nil {
+		return nil, err
+	}
+	commands := settings.Commands
+
+	if len(osArgs) > 0 {
+		// This should always be the case in production
+		osArgs = osArgs[1:] // remove the program name
+	}
+
+	for len(osArgs) > 0 {
+		osArg := osArgs[0]
+		osArgs = osArgs[1:]
+		switch {
+		case osArg == "--":
+			source.remainder = slices.Clone(osArgs)
+			return source, nil
+		case !isFlag(osArg):
+			command, found := findCommand(commands, osArg)
+			if !found || len(source.args) > 0 {
+				source.args = append(source.args, osArg)
+				continue
+			}
+			source.commands = append(source.commands, command.Name)
+			commands = command.Commands
+			for _, definition := range command.Definitions {
+				err = source.addDefinition(definition)
+				if err != nil { // should not happen since commands are validated
+					return nil, fmt.Errorf("command %s: %w", command.Name, err)
+				}
+			}
+		case strings.HasPrefix(osArg, "--") || source.isLongSingleDash(osArg):
+			osArgs, err = source.parseLong(osArg, osArgs, settings.RejectUnknown)
+		default:
+			osArgs, err = source.parseShorts(osArg, osArgs, settings.RejectUnknown)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return source, nil
+}
+
+func newTypedSource() *Source {
+	return &Source{
+		keyToValues: make(map[string][]string),
+		definitions: make(map[string]Definition),
+		shortToKey:  make(map[rune]string),
+	}
+}
+
+// validateCommands validates the commands given recursively,
+// checking their definitions together with their parent
+// definitions given.
+func validateCommands(parentDefinitions []Definition, commands []Command) (err error) {
+	names := make(map[string]struct{}, len(commands))
+	for _, command := range commands {
+		switch {
+		case command.Name == "":
+			return fmt.Errorf("%w: command name is empty", ErrDefinitionNotValid)
+		case strings.HasPrefix(command.Name, "-"):
+			return fmt.Errorf("%w: command name %s starts with a dash",
+				ErrDefinitionNotValid, command.Name)
+		}
+		_, exists := names[command.Name]
+		if exists {
+			return fmt.Errorf("%w: command %s is defined more than once",
+				ErrDefinitionNotValid, command.Name)
+		}
+		names[command.Name] = struct{}{}
+
+		definitions := make([]Definition, 0, len(parentDefinitions)+len(command.Definitions))
+		definitions = append(definitions, parentDefinitions...)
+		definitions = append(definitions, command.Definitions...)
+		source := newTypedSource()
+		for _, definition := range definitions {
+			err = source.addDefinition(definition)
+			if err != nil {
+				return fmt.Errorf("command %s: %w", command.Name, err)
+			}
+		}
+
+		err = validateCommands(definitions, command.Commands)
+		if err != nil {
+			return fmt.Errorf("command %s: %w", command.Name, err)
+		}
+	}
+	return nil
+}
+
+func findCommand(commands []Command, name string) (command Command, found bool) {
+	for _, command := range commands {
+		if command.Name == name {
+			return command, true
+		}
+	}
+	return Command{}, false
+}
+
+func (f *Source) addDefinition(definition Definition) (err error) {
+	definition.Key = f.KeyTransform(definition.Key)
+	if definition.Key == "" {
+		return fmt.Errorf("%w: key is empty", ErrDefinitionNotValid)
+	}
+	_, exists := f.definitions[definition.Key]
+	if exists {
+		return fmt.Errorf("%w: key %s is defined more than once",
+			ErrDefinitionNotValid, definition.Key)
+	}
+
+	if definition.Short != 0 {
+		if definition.Short == '-' || definition.Short == '=' {
+			return fmt.Errorf("%w: short alias %q of key %s is not allowed",
+				ErrDefinitionNotValid, definition.Short, definition.Key)
+		}
+		existingKey, exists := f.shortToKey[definition.Short]
+		if exists {
+			return fmt.Errorf("%w: short alias %q is used by both keys %s and %s",
+				ErrDefinitionNotValid, definition.Short, existingKey, definition.Key)
+		}
+		f.shortToKey[definition.Short] = definition.Key
+	}
+
+	f.definitions[definition.Key] = definition
+	return nil
+}
+
+// isLongSingleDash returns true if the single dash argument given
+// should be parsed as a long flag, which is the case if its name is
+// a declared flag key, or if its first character is not a declared
+// short alias.
+func (f *Source) isLongSingleDash(osArg string) bool {
+	name, _, _ := strings.Cut(osArg[1:], "=")
+	_, isKey := f.definitions[f.KeyTransform(name)]
+	if isKey {
+		return true
+	}
+	firstRune, _ := utf8.DecodeRuneInString(name)
+	_, isShort := f.shortToKey[firstRune]
+	return !isShort
+}
+
+func (f *Source) parseLong(osArg string, osArgs []string,
+	rejectUnknown bool) (nextOsArgs []string, err error) {
+	name, value, hasValue := strings.Cut(strings.TrimLeft(osArg, "-"), "=")
+	key := f.KeyTransform(name)
+
+	definition, known := f.definitions[key]
+	if !known {
+		if rejectUnknown {
+			return nil, fmt.Errorf("%w: %s", ErrFlagUnknown, osArg)
+		}
+		key, value, osArgs = parseOne(append([]string{osArg}, osArgs...))
+		key = f.KeyTransform(key)
+		f.keyToValues[key] = append(f.keyToValues[key], value)
+		return osArgs, nil
+	}
+
+	switch {
+	case hasValue:
+	case definition.Boolean:
+		value = "true"
+	case len(osArgs) == 0:
+		return nil, fmt.Errorf("%w: %s", ErrFlagValueMissing, osArg)
+	default:
+		value = osArgs[0]
+		osArgs = osArgs[1:]
+	}
+
+	return osArgs, f.set(definition, value)
+}
+
+func (f *Source) parseShorts(osArg string, osArgs []string,
+	rejectUnknown bool) (nextOsArgs []string, err error) {
+	shorts := osArg[1:]
+	for i, short := range shorts {
+		rest := shorts[i+utf8.RuneLen(short):]
+
+		key, known := f.shortToKey[short]
+		if !known {
+			if rejectUnknown {
+				return nil, fmt.Errorf("%w: -%c in %s", ErrFlagUnknown, short, osArg)
+			}
+			key = f.KeyTransform(string(short))
+			f.keyToValues[key] = append(f.keyToValues[key], "true")
+			continue
+		}
+		definition := f.definitions[key]
+
+		switch {
+		case strings.HasPrefix(rest, "="):
+			return osArgs, f.set(definition, rest[1:])
+		case definition.Boolean:
+			err = f.set(definition, "true")
+			if err != nil {
+				return nil, err
+			}
+			continue
+		case rest != "":
+			return osArgs, f.set(definition, rest)
+		case len(osArgs) == 0:
+			return nil, fmt.Errorf("%w: -%c", ErrFlagValueMissing, short)
+		default:
+			return osArgs[1:], f.set(definition, osArgs[0])
+		}
+	}
+	return osArgs, nil
+}
+
+func (f *Source) set(definition Definition, value string) (err error) {
+	_, repeated := f.keyToValues[definition.Key]
+	if repeated && !definition.Repeatable {
+		return fmt.Errorf("%w: %s", ErrFlagRepeated, f.FormatKey(definition.Key))
+	}
+	f.keyToValues[definition.Key] = append(f.keyToValues[definition.Key], value)
+	return nil
+}
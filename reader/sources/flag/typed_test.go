@@ -0,0 +1,350 @@
+package flag
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_NewTyped(t *testing.T) {
+	t.Parallel()
+
+	definitions := []Definition{
+		{Key: "VERBOSE", Short: 'v', Boolean: true},
+		{Key: "ENABLED", Boolean: true},
+		{Key: "all", Short: 'a', Boolean: true},
+		{Key: "output", Short: 'o'},
+		{Key: "HEADER", Short: 'H', Repeatable: true},
+		{Key: "log"},
+	}
+
+	testCases := map[string]struct {
+		osArgs      []string
+		settings    Settings
+		keyToValues map[string][]string
+		errWrapped  error
+		errMessage  string
+	}{
+		"empty": {
+			osArgs:      []string{"program"},
+			keyToValues: map[string][]string{},
+		},
+		"boolean_before_command": {
+			osArgs:      []string{"program", "--enabled", "command"},
+			settings:    Settings{Definitions: definitions},
+			keyToValues: map[string][]string{"enabled": {"true"}},
+		},
+		"boolean_with_value": {
+			osArgs:      []string{"program", "--enabled=false", "-v=false"},
+			settings:    Settings{Definitions: definitions},
+			keyToValues: map[string][]string{"enabled": {"false"}, "verbose": {"false"}},
+		},
+		"valued_flags": {
+			osArgs: []string{"program", "--output", "-", "-log=yes",
+				"command", "--header=a", "-H", "b"},
+			settings: Settings{Definitions: definitions},
+			keyToValues: map[string][]string{
+				"output": {"-"},
+				"log":    {"yes"},
+				"header": {"a", "b"},
+			},
+		},
+		"combined_shorts_repeated": {
+			osArgs:     []string{"program", "-va", "-aofile"},
+			settings:   Settings{Definitions: definitions, RejectUnknown: true},
+			errWrapped: ErrFlagRepeated,
+			errMessage: "flag is given more than once: -a, --all",
+		},
+		"combined_shorts_with_value": {
+			osArgs:   []string{"program", "-vao", "file", "command"},
+			settings: Settings{Definitions: definitions},
+			keyToValues: map[string][]string{
+				"verbose": {"true"},
+				"all":     {"true"},
+				"output":  {"file"},
+			},
+		},
+		"combined_shorts_with_attached_value": {
+			osArgs:      []string{"program", "-vofile"},
+			settings:    Settings{Definitions: definitions},
+			keyToValues: map[string][]string{"verbose": {"true"}, "output": {"file"}},
+		},
+		"terminator": {
+			osArgs:      []string{"program", "-v", "--", "--enabled"},
+			settings:    Settings{Definitions: definitions},
+			keyToValues: map[string][]string{"verbose": {"true"}},
+		},
+		"unknown_flags_untyped": {
+			osArgs:   []string{"program", "--metrics", "prometheus", "-vx", "--rpc"},
+			settings: Settings{Definitions: definitions},
+			keyToValues: map[string][]string{
+				"metrics": {"prometheus"},
+				"verbose": {"true"},
+				"x":       {"true"},
+				"rpc":     {"true"},
+			},
+		},
+		"unknown_long_flag_rejected": {
+			osArgs:     []string{"program", "--metrics=prometheus"},
+			settings:   Settings{Definitions: definitions, RejectUnknown: true},
+			errWrapped: ErrFlagUnknown,
+			errMessage: "flag is unknown: --metrics=prometheus",
+		},
+		"unknown_short_flag_rejected": {
+			osArgs:     []string{"program", "-vx"},
+			settings:   Settings{Definitions: definitions, RejectUnknown: true},
+			errWrapped: ErrFlagUnknown,
+			errMessage: "flag is unknown: -x in -vx",
+		},
+		"long_value_missing": {
+			osArgs:     []string{"program", "--output"},
+			settings:   Settings{Definitions: definitions},
+			errWrapped: ErrFlagValueMissing,
+			errMessage: "flag value is missing: --output",
+		},
+		"short_value_missing": {
+			osArgs:     []string{"program", "-vo"},
+			settings:   Settings{Definitions: definitions},
+			errWrapped: ErrFlagValueMissing,
+			errMessage: "flag value is missing: -o",
+		},
+		"flag_repeated": {
+			osArgs:     []string{"program", "--output=a", "-o", "b"},
+			settings:   Settings{Definitions: definitions},
+			errWrapped: ErrFlagRepeated,
+			errMessage: "flag is given more than once: -o, --output",
+		},
+		"definition_key_empty": {
+			settings: Settings{Definitions: []Definition{
+				{Short: 'v'},
+			}},
+			errWrapped: ErrDefinitionNotValid,
+			errMessage: "flag definition is not valid: key is empty",
+		},
+		"definition_key_duplicate": {
+			settings: Settings{Definitions: []Definition{
+				{Key: "VERBOSE"}, {Key: "verbose"},
+			}},
+			errWrapped: ErrDefinitionNotValid,
+			errMessage: "flag definition is not valid: key verbose is defined more than once",
+		},
+		"definition_short_duplicate": {
+			settings: Settings{Definitions: []Definition{
+				{Key: "verbose", Short: 'v'}, {Key: "version", Short: 'v'},
+			}},
+			errWrapped: ErrDefinitionNotValid,
+			errMessage: "flag definition is not valid: short alias 'v' " +
+				"is used by both keys verbose and version",
+		},
+		"definition_short_not_allowed": {
+			settings: Settings{Definitions: []Definition{
+				{Key: "verbose", Short: '='},
+			}},
+			errWrapped: ErrDefinitionNotValid,
+			errMessage: "flag definition is not valid: short alias '=' of key verbose is not allowed",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			source, err := NewTyped(testCase.osArgs, testCase.settings)
+
+			if !errors.Is(err, testCase.errWrapped) {
+				t.Errorf("expected error %v to wrap %v", err, testCase.errWrapped)
+			}
+			if testCase.errWrapped != nil {
+				if err.Error() != testCase.errMessage {
+					t.Errorf("expected error message %q, got %q",
+						testCase.errMessage, err.Error())
+				}
+				return
+			}
+			if !reflect.DeepEqual(source.keyToValues, testCase.keyToValues) {
+				t.Errorf("expected %#v, got %#v", testCase.keyToValues, source.keyToValues)
+			}
+		})
+	}
+}
+
+func Test_Source_FormatKey(t *testing.T) {
+	t.Parallel()
+
+	source, err := NewTyped(nil, Settings{
+		Definitions: []Definition{{Key: "VERBOSE", Short: 'v'}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		key       string
+		formatted string
+	}{
+		"with_short_alias": {
+			key:       "VERBOSE",
+			formatted: "-v, --verbose",
+		},
+		"without_short_alias": {
+			key:       "SERVER_ADDRESS",
+			formatted: "--server-address",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			formatted := source.FormatKey(testCase.key)
+
+			if formatted != testCase.formatted {
+				t.Errorf("expected %q, got %q", testCase.formatted, formatted)
+			}
+		})
+	}
+}
+
+func Test_NewTyped_commands(t *testing.T) {
+	t.Parallel()
+
+	settings := Settings{
+		Definitions: []Definition{
+			{Key: "VERBOSE", Short: 'v', Boolean: true},
+		},
+		RejectUnknown: true,
+		Commands: []Command{
+			{
+				Name:        "serve",
+				Definitions: []Definition{{Key: "PORT", Short: 'p'}},
+			},
+			{
+				Name:        "migrate",
+				Definitions: []Definition{{Key: "DRY_RUN", Short: 'p', Boolean: true}},
+				Commands: []Command{
+					{Name: "up"},
+					{Name: "down", Definitions: []Definition{{Key: "STEPS"}}},
+				},
+			},
+		},
+	}
+
+	testCases := map[string]struct {
+		osArgs      []string
+		settings    Settings
+		keyToValues map[string][]string
+		commands    []string
+		args        []string
+		remainder   []string
+		errWrapped  error
+		errMessage  string
+	}{
+		"no_command": {
+			osArgs:      []string{"program", "-v", "file"},
+			settings:    settings,
+			keyToValues: map[string][]string{"verbose": {"true"}},
+			args:        []string{"file"},
+		},
+		"command_with_global_flag_after": {
+			osArgs:      []string{"program", "serve", "-p", "8000", "--verbose"},
+			settings:    settings,
+			keyToValues: map[string][]string{"port": {"8000"}, "verbose": {"true"}},
+			commands:    []string{"serve"},
+		},
+		"command_flag_before_command": {
+			osArgs:     []string{"program", "--port", "8000", "serve"},
+			settings:   settings,
+			errWrapped: ErrFlagUnknown,
+			errMessage: "flag is unknown: --port",
+		},
+		"sibling_command_flag": {
+			osArgs:     []string{"program", "migrate", "--port", "8000"},
+			settings:   settings,
+			errWrapped: ErrFlagUnknown,
+			errMessage: "flag is unknown: --port",
+		},
+		"short_alias_in_command_namespace": {
+			osArgs:      []string{"program", "migrate", "-pv", "up"},
+			settings:    settings,
+			keyToValues: map[string][]string{"dry-run": {"true"}, "verbose": {"true"}},
+			commands:    []string{"migrate", "up"},
+		},
+		"sub_command": {
+			osArgs: []string{"program", "-v", "migrate", "down",
+				"--steps", "2", "serve", "--", "--steps", "3"},
+			settings:    settings,
+			keyToValues: map[string][]string{"verbose": {"true"}, "steps": {"2"}},
+			commands:    []string{"migrate", "down"},
+			args:        []string{"serve"},
+			remainder:   []string{"--steps", "3"},
+		},
+		"command_after_positional_argument": {
+			osArgs:      []string{"program", "file", "serve"},
+			settings:    settings,
+			keyToValues: map[string][]string{},
+			args:        []string{"file", "serve"},
+		},
+		"command_name_empty": {
+			settings: Settings{Commands: []Command{
+				{Name: "migrate", Commands: []Command{{}}},
+			}},
+			errWrapped: ErrDefinitionNotValid,
+			errMessage: "command migrate: flag definition is not valid: command name is empty",
+		},
+		"command_name_with_dash": {
+			settings:   Settings{Commands: []Command{{Name: "-serve"}}},
+			errWrapped: ErrDefinitionNotValid,
+			errMessage: "flag definition is not valid: command name -serve starts with a dash",
+		},
+		"command_duplicate": {
+			settings:   Settings{Commands: []Command{{Name: "serve"}, {Name: "serve"}}},
+			errWrapped: ErrDefinitionNotValid,
+			errMessage: "flag definition is not valid: command serve is defined more than once",
+		},
+		"command_key_same_as_global_key": {
+			settings: Settings{
+				Definitions: []Definition{{Key: "PORT"}},
+				Commands: []Command{
+					{Name: "serve", Definitions: []Definition{{Key: "port"}}},
+				},
+			},
+			errWrapped: ErrDefinitionNotValid,
+			errMessage: "command serve: flag definition is not valid: " +
+				"key port is defined more than once",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			source, err := NewTyped(testCase.osArgs, testCase.settings)
+
+			if !errors.Is(err, testCase.errWrapped) {
+				t.Errorf("expected error %v to wrap %v", err, testCase.errWrapped)
+			}
+			if testCase.errWrapped != nil {
+				if err.Error() != testCase.errMessage {
+					t.Errorf("expected error message %q, got %q",
+						testCase.errMessage, err.Error())
+				}
+				return
+			}
+			if !reflect.DeepEqual(source.keyToValues, testCase.keyToValues) {
+				t.Errorf("expected %#v, got %#v", testCase.keyToValues, source.keyToValues)
+			}
+			if !reflect.DeepEqual(source.Commands(), testCase.commands) {
+				t.Errorf("expected commands %#v, got %#v", testCase.commands, source.Commands())
+			}
+			if !reflect.DeepEqual(source.Args(), testCase.args) {
+				t.Errorf("expected args %#v, got %#v", testCase.args, source.Args())
+			}
+			if !reflect.DeepEqual(source.Remainder(), testCase.remainder) {
+				t.Errorf("expected remainder %#v, got %#v", testCase.remainder, source.Remainder())
+			}
+		})
+	}
+}
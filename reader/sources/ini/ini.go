@@ -0,0 +1,105 @@
+package ini
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/qdm12/gosettings/internal/flatten"
+	"golang.org/x/exp/maps"
+)
+
+// Source implements an INI file settings source.
+// Section names are used as key prefixes, such that
+// `host = localhost` in the `[db]` section is accessible
+// with the key DB_HOST.
+// Note all keys are transformed using its KeyTransform method.
+type Source struct {
+	path       string
+	keyToValue map[string]string
+	keyToLine  map[string]int
+}
+
+// New creates a new INI file source by reading and parsing
+// the INI file at the path given in settings.
+// The file format supported is:
+//   - empty lines and lines starting with `;` or `#` are ignored
+//   - `[section]` headers prefix the keys defined after them with
+//     the section name, and `[section.sub]` headers are supported
+//   - `key = value` or `key: value` pairs, with the key and value
+//     trimmed from their surrounding spaces
+//   - inline comments starting with `;` or `#` preceded by a space
+//     or tab, such as `key = value ; comment`. Values containing
+//     ` ;` or ` #` must therefore be quoted, such as `key = "a ; b"`
+//   - a value line ending with a backslash is continued on the
+//     next line, with the backslash and the next line leading spaces
+//     removed.
+//
+// An error is returned if the settings are not valid, if the file
+// cannot be read or parsed, with the line number in the error message,
+// or if a key is defined more than once and the duplicate keys
+// policy is DuplicateKeysError.
+func New(settings Settings) (source *Source, err error) {
+	settings.setDefaults()
+	err = settings.validate()
+	if err != nil {
+		return nil, fmt.Errorf("validating settings: %w", err)
+	}
+
+	content, err := os.ReadFile(settings.Path)
+	if err != nil {
+		return nil, fmt.Errorf("reading INI file: %w", err)
+	}
+
+	keyToValue, keyToLine, err := parse(string(content), settings.DuplicateKeys)
+	if err != nil {
+		return nil, fmt.Errorf("parsing INI file %s: %w", settings.Path, err)
+	}
+
+	return &Source{
+		path:       settings.Path,
+		keyToValue: keyToValue,
+		keyToLine:  keyToLine,
+	}, nil
+}
+
+func (s *Source) String() string {
+	return "INI file " + s.path
+}
+
+// Get returns the value of the INI key given, and a
+// boolean `isSet` to indicate if it is set or not.
+func (s *Source) Get(key string) (value string, isSet bool) {
+	value, isSet = s.keyToValue[key]
+	return value, isSet
+}
+
+// KeyTransform transforms a generic key to an INI key
+// prefixed with its section name. It notably:
+// - Changes all characters to be uppercase
+// - Replaces all dashes, dots and spaces with underscores.
+func (s *Source) KeyTransform(key string) (newKey string) {
+	return flatten.NormalizeKey(key)
+}
+
+// Keys returns all the keys set in the INI file.
+func (s *Source) Keys() (keys []string) {
+	return maps.Keys(s.keyToValue)
+}
+
+// Locate returns the line where the value for the given
+// key is defined in the INI file, in the form `line 5`.
+// It returns the empty string if the key is not found.
+func (s *Source) Locate(key string) (location string) {
+	line, ok := s.keyToLine[key]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("line %d", line)
+}
+
+func normalizeKey(section, name string) (key string) {
+	if section != "" {
+		name = section + "_" + name
+	}
+	return flatten.NormalizeKey(name)
+}
@@ -0,0 +1,142 @@
+package ini
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrSectionNotClosed = errors.New("section header is not closed")
+	ErrSectionEmpty     = errors.New("section name is empty")
+	ErrSeparatorMissing = errors.New("equal sign or colon is missing")
+	ErrKeyEmpty         = errors.New("key is empty")
+	ErrKeyDuplicate     = errors.New("duplicate key")
+)
+
+// parse parses the INI content given, and returns a map of
+// normalized keys to values, and a map of normalized keys to
+// the line number where their value is defined.
+func parse(content string, duplicateKeys DuplicateKeys) (
+	keyToValue map[string]string, keyToLine map[string]int, err error) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	lines := strings.Split(content, "\n")
+	keyToValue = make(map[string]string)
+	keyToLine = make(map[string]int)
+	section := ""
+
+	for i := 0; i < len(lines); i++ {
+		lineNumber := i + 1
+		line := trimSpaces(lines[i])
+		switch {
+		case line == "", line[0] == ';', line[0] == '#':
+			continue
+		case line[0] == '[':
+			section, err = parseSection(line)
+			if err != nil {
+				return nil, nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			continue
+		}
+
+		name, value, found := cutSeparator(line)
+		if !found {
+			return nil, nil, fmt.Errorf("line %d: %w: %s",
+				lineNumber, ErrSeparatorMissing, line)
+		}
+		name = trimSpaces(name)
+		if name == "" {
+			return nil, nil, fmt.Errorf("line %d: %w", lineNumber, ErrKeyEmpty)
+		}
+
+		value, extraLines := parseValue(value, lines[i+1:])
+		i += extraLines
+
+		key := normalizeKey(section, name)
+		previousLine, exists := keyToLine[key]
+		if exists {
+			switch duplicateKeys {
+			case DuplicateKeysFirst:
+				continue
+			case DuplicateKeysLast:
+			default:
+				return nil, nil, fmt.Errorf("line %d: %w: %s (first defined at line %d)",
+					lineNumber, ErrKeyDuplicate, key, previousLine)
+			}
+		}
+		keyToValue[key] = value
+		keyToLine[key] = lineNumber
+	}
+
+	return keyToValue, keyToLine, nil
+}
+
+// parseSection parses a section header line such as `[section]`
+// and returns the section name. A comment can follow the header.
+func parseSection(line string) (section string, err error) {
+	section, rest, found := strings.Cut(line[1:], "]")
+	if !found {
+		return "", fmt.Errorf("%w: %s", ErrSectionNotClosed, line)
+	}
+	rest = trimSpaces(rest)
+	if rest != "" && rest[0] != ';' && rest[0] != '#' {
+		return "", fmt.Errorf("%w: %s", ErrSectionNotClosed, line)
+	}
+
+	section = trimSpaces(section)
+	if section == "" {
+		return "", ErrSectionEmpty
+	}
+	return section, nil
+}
+
+// cutSeparator cuts the line at the first equal sign or colon.
+func cutSeparator(line string) (name, value string, found bool) {
+	index := strings.IndexAny(line, "=:")
+	if index == -1 {
+		return "", "", false
+	}
+	return line[:index], line[index+1:], true
+}
+
+// parseValue parses the value starting with the first line given,
+// and continues on the next lines if the value ends with a backslash.
+// It returns the value and the number of extra lines used.
+// Inline comments are removed from each line.
+func parseValue(firstLine string, nextLines []string) (value string, extraLines int) {
+	value = trimSpaces(cutInlineComment(firstLine))
+	for strings.HasSuffix(value, `\`) && extraLines < len(nextLines) {
+		nextLine := trimSpaces(cutInlineComment(nextLines[extraLines]))
+		value = strings.TrimSuffix(value, `\`) + nextLine
+		extraLines++
+	}
+	return value, extraLines
+}
+
+// cutInlineComment removes the inline comment starting with
+// a `;` or `#` character preceded by a space or tab, such that
+// `value ; comment` becomes `value ` but `a;b` is kept as is.
+// Spans quoted with `"` or `'` are skipped, where an opening quote
+// must be at the start of the string or preceded by a space or tab,
+// such that `"a ; b" ; comment` becomes `"a ; b" `.
+func cutInlineComment(s string) string {
+	for i := 0; i < len(s); i++ {
+		afterSpace := i == 0 || s[i-1] == ' ' || s[i-1] == '\t'
+		switch {
+		case !afterSpace:
+		case s[i] == '"' || s[i] == '\'':
+			closingIndex := strings.IndexByte(s[i+1:], s[i])
+			if closingIndex == -1 {
+				return s
+			}
+			i += 1 + closingIndex
+		case (s[i] == ';' || s[i] == '#') && i > 0:
+			return s[:i]
+		}
+	}
+	return s
+}
+
+func trimSpaces(s string) string {
+	return strings.Trim(s, " \t")
+}
@@ -0,0 +1,51 @@
+package ini
+
+import (
+	"fmt"
+
+	"github.com/qdm12/gosettings"
+	"github.com/qdm12/gosettings/validate"
+)
+
+// DuplicateKeys is the policy to apply when a key is
+// defined more than once in the INI file.
+type DuplicateKeys string
+
+const (
+	// DuplicateKeysError makes the source creation fail
+	// if a key is defined more than once.
+	DuplicateKeysError DuplicateKeys = "error"
+	// DuplicateKeysFirst keeps the first value defined
+	// for a key, ignoring the following ones.
+	DuplicateKeysFirst DuplicateKeys = "first"
+	// DuplicateKeysLast keeps the last value defined
+	// for a key, overriding the previous ones.
+	DuplicateKeysLast DuplicateKeys = "last"
+)
+
+// Settings contains settings for the INI file source.
+type Settings struct {
+	// Path is the path to the INI file to read.
+	// It defaults to "config.ini".
+	Path string
+	// DuplicateKeys is the policy to apply when a key is
+	// defined more than once, where a key is the combination
+	// of its section and its name. It can be one of
+	// DuplicateKeysError, DuplicateKeysFirst or DuplicateKeysLast,
+	// and defaults to DuplicateKeysError.
+	DuplicateKeys DuplicateKeys
+}
+
+func (s *Settings) setDefaults() {
+	s.Path = gosettings.DefaultComparable(s.Path, "config.ini")
+	s.DuplicateKeys = gosettings.DefaultComparable(s.DuplicateKeys, DuplicateKeysError)
+}
+
+func (s Settings) validate() (err error) {
+	err = validate.IsOneOf(s.DuplicateKeys,
+		DuplicateKeysError, DuplicateKeysFirst, DuplicateKeysLast)
+	if err != nil {
+		return fmt.Errorf("duplicate keys policy: %w", err)
+	}
+	return nil
+}
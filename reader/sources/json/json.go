@@ -0,0 +1,138 @@
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/qdm12/gosettings/internal/flatten"
+	"golang.org/x/exp/maps"
+)
+
+// Source implements a JSON file settings source.
+// Nested object fields are flattened into keys joined with
+// underscores, such that `{"server": {"listen_address": ""}}`
+// is accessible with the key SERVER_LISTEN_ADDRESS.
+// Note all keys are transformed using its KeyTransform method.
+type Source struct {
+	path       string
+	keyToValue map[string]string
+}
+
+// New creates a new JSON file source by reading and decoding
+// the JSON object in the file at the path given in settings.
+// Values are converted to strings such that:
+//   - strings are kept as they are
+//   - numbers are kept as written in the file
+//   - booleans are converted to "true" or "false"
+//   - null values are considered as unset
+//   - arrays of non-object and non-array values are converted to
+//     comma separated values, to be used with the CSV methods,
+//     and cannot contain elements with a comma
+//   - other arrays are flattened using the element index as key,
+//     for example SERVERS_0_ADDRESS.
+//
+// An error is returned if the file cannot be read, is not a
+// JSON object or if two different JSON keys result in the same
+// flattened key.
+func New(settings Settings) (source *Source, err error) {
+	settings.setDefaults()
+
+	content, err := os.ReadFile(settings.Path)
+	if err != nil {
+		return nil, fmt.Errorf("reading JSON file: %w", err)
+	}
+
+	keyToValue, err := parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("parsing JSON file %s: %w", settings.Path, err)
+	}
+
+	return &Source{
+		path:       settings.Path,
+		keyToValue: keyToValue,
+	}, nil
+}
+
+func (s *Source) String() string {
+	return "JSON file " + s.path
+}
+
+// Get returns the value of the flattened JSON key
+// given, and a boolean `isSet` to indicate if it is
+// set or not.
+func (s *Source) Get(key string) (value string, isSet bool) {
+	value, isSet = s.keyToValue[key]
+	return value, isSet
+}
+
+// KeyTransform transforms a generic key to a flattened
+// JSON key. It notably:
+// - Changes all characters to be uppercase
+// - Replaces all dashes, dots and spaces with underscores.
+func (s *Source) KeyTransform(key string) (newKey string) {
+	return flatten.NormalizeKey(key)
+}
+
+// Keys returns all the keys set in the JSON file.
+func (s *Source) Keys() (keys []string) {
+	return maps.Keys(s.keyToValue)
+}
+
+var (
+	ErrNotObject    = errors.New("JSON document is not an object")
+	ErrTrailingData = errors.New("trailing data after JSON document")
+	ErrKeyConflict  = flatten.ErrKeyConflict
+	// ErrArrayElementComma is returned if an element of an array
+	// converted to comma separated values contains a comma.
+	ErrArrayElementComma = flatten.ErrArrayElementComma
+)
+
+func parse(content []byte) (keyToValue map[string]string, err error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	var root any
+	err = decoder.Decode(&root)
+	if err != nil {
+		return nil, fmt.Errorf("decoding JSON: %w", withPosition(content, err))
+	}
+
+	_, err = decoder.Token()
+	if !errors.Is(err, io.EOF) {
+		line, column := position(content, decoder.InputOffset())
+		return nil, fmt.Errorf("%w: at line %d column %d",
+			ErrTrailingData, line, column)
+	}
+
+	object, ok := root.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrNotObject, root)
+	}
+
+	return flatten.Flatten(object)
+}
+
+// withPosition adds the line and column to the error message if
+// the error given is a JSON syntax error.
+func withPosition(content []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		return err
+	}
+	// The syntax error offset is right after the offending character.
+	line, column := position(content, syntaxErr.Offset-1)
+	return fmt.Errorf("line %d column %d: %w", line, column, err)
+}
+
+// position returns the 1-indexed line and column of the
+// given byte offset in the content.
+func position(content []byte, offset int64) (line, column int) {
+	offset = max(0, min(offset, int64(len(content))))
+	before := content[:offset]
+	line = 1 + bytes.Count(before, []byte{'\n'})
+	column = 1 + len(before) - (bytes.LastIndexByte(before, '\n') + 1)
+	return line, column
+}
@@ -0,0 +1,195 @@
+package json
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "config.json")
+		const content = `{"server": {"listen_address": ":8000"}}`
+		err := os.WriteFile(path, []byte(content), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		source, err := New(Settings{Path: path})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expectedSource := &Source{
+			path: path,
+			keyToValue: map[string]string{
+				"SERVER_LISTEN_ADDRESS": ":8000",
+			},
+		}
+		if !reflect.DeepEqual(source, expectedSource) {
+			t.Errorf("expected source %#v, got %#v", expectedSource, source)
+		}
+
+		key := source.KeyTransform("server.listen_address")
+		value, isSet := source.Get(key)
+		if !isSet || value != ":8000" {
+			t.Errorf("expected value :8000 to be set, got %q (set %t)", value, isSet)
+		}
+	})
+
+	t.Run("parse_error", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "config.json")
+		err := os.WriteFile(path, []byte("{\n\"key\": tru\n}"), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = New(Settings{Path: path})
+		errRegex := regexp.MustCompile(`^parsing JSON file /.+/config\.json: ` +
+			`decoding JSON: line 2 column 11: invalid character '\\n' in literal true \(expecting 'e'\)$`)
+		if err == nil || !errRegex.MatchString(err.Error()) {
+			t.Errorf("expected error to match %s, got %v", errRegex, err)
+		}
+	})
+
+	t.Run("file_not_found", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "config.json")
+		_, err := New(Settings{Path: path})
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected error %v to wrap %v", err, os.ErrNotExist)
+		}
+	})
+}
+
+func Test_parse(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		content    string
+		keyToValue map[string]string
+		errWrapped error
+		errMessage string
+	}{
+		"empty_object": {
+			content:    "{}",
+			keyToValue: map[string]string{},
+		},
+		"scalars": {
+			content: `{"string": "Value", "int": 10, "float": 1.50,
+				"true": true, "false": false, "null": null, "empty": ""}`,
+			keyToValue: map[string]string{
+				"STRING": "Value",
+				"INT":    "10",
+				"FLOAT":  "1.50",
+				"TRUE":   "true",
+				"FALSE":  "false",
+				"EMPTY":  "",
+			},
+		},
+		"nested_objects": {
+			content: `{"server": {"listen-address": ":8000",
+				"tls": {"enabled": true}}, "log.level": "info"}`,
+			keyToValue: map[string]string{
+				"SERVER_LISTEN_ADDRESS": ":8000",
+				"SERVER_TLS_ENABLED":    "true",
+				"LOG_LEVEL":             "info",
+			},
+		},
+		"arrays": {
+			content: `{"names": ["a", "b", null, 3], "empty": [],
+				"servers": [{"address": "1.2.3.4"}, [1, 2]]}`,
+			keyToValue: map[string]string{
+				"NAMES":             "a,b,3",
+				"EMPTY":             "",
+				"SERVERS_0_ADDRESS": "1.2.3.4",
+				"SERVERS_1":         "1,2",
+			},
+		},
+		"not_object": {
+			content:    `["a"]`,
+			errWrapped: ErrNotObject,
+			errMessage: "JSON document is not an object: []interface {}",
+		},
+		"trailing_data": {
+			content:    "{}\n{}",
+			errWrapped: ErrTrailingData,
+			errMessage: "trailing data after JSON document: at line 2 column 2",
+		},
+		"array_element_with_comma": {
+			content:    `{"server": {"names": ["a", "b,c"]}}`,
+			errWrapped: ErrArrayElementComma,
+			errMessage: `array element contains a comma: server.names[1] is "b,c"`,
+		},
+		"key_conflict": {
+			content:    `{"a": {"b": 1}, "a_b": 2}`,
+			errWrapped: ErrKeyConflict,
+			errMessage: "flattened key conflict: a.b and a_b both map to A_B",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			keyToValue, err := parse([]byte(testCase.content))
+
+			if !errors.Is(err, testCase.errWrapped) {
+				t.Errorf("expected error %v to wrap %v", err, testCase.errWrapped)
+			}
+			if testCase.errWrapped != nil {
+				if err.Error() != testCase.errMessage {
+					t.Errorf("expected error message %q, got %q",
+						testCase.errMessage, err.Error())
+				}
+				return
+			}
+			if !reflect.DeepEqual(keyToValue, testCase.keyToValue) {
+				t.Errorf("expected %#v, got %#v", testCase.keyToValue, keyToValue)
+			}
+		})
+	}
+}
+
+func Test_Source_KeyTransform(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		key    string
+		newKey string
+	}{
+		"empty": {},
+		"no_change": {
+			key:    "SERVER_ADDRESS",
+			newKey: "SERVER_ADDRESS",
+		},
+		"json_path": {
+			key:    "server.listen-address",
+			newKey: "SERVER_LISTEN_ADDRESS",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			source := &Source{}
+			newKey := source.KeyTransform(testCase.key)
+			if newKey != testCase.newKey {
+				t.Errorf("expected %s, got %s", testCase.newKey, newKey)
+			}
+		})
+	}
+}
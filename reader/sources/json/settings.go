@@ -0,0 +1,16 @@
+package json
+
+import (
+	"github.com/qdm12/gosettings"
+)
+
+// Settings contains settings for the JSON file source.
+type Settings struct {
+	// Path is the path to the JSON file to read.
+	// It defaults to "config.json".
+	Path string
+}
+
+func (s *Settings) setDefaults() {
+	s.Path = gosettings.DefaultComparable(s.Path, "config.json")
+}
@@ -0,0 +1,179 @@
+package reload
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Wrapped is the interface the wrapped source must implement.
+// It is implemented by all the file sources of this module,
+// such as the dotenv, JSON, YAML, TOML, INI and secrets
+// directory sources.
+type Wrapped interface {
+	String() string
+	Get(key string) (value string, isSet bool)
+	KeyTransform(key string) string
+	Keys() (keys []string)
+}
+
+// Source implements a reloadable settings source, wrapping
+// a source created by a load function which can be called
+// again to reload the source, for example when its file changes.
+// It implements the reader Reloader interface so it can be
+// watched with the reader Watch method.
+// It is safe for concurrent use.
+type Source struct {
+	load  func() (source Wrapped, err error)
+	paths []string
+
+	mutex  sync.RWMutex
+	source Wrapped
+	// values is a snapshot of all the key values of the source,
+	// taken when loading it, to detect changed keys on reload.
+	values map[string]string
+}
+
+var (
+	ErrLoadNotSet  = errors.New("load function is not set")
+	ErrPathsNotSet = errors.New("paths to watch are not set")
+)
+
+// New creates a new reloadable source using the settings given,
+// calling the Load function of the settings once.
+func New(settings Settings) (source *Source, err error) {
+	err = settings.validate()
+	if err != nil {
+		return nil, fmt.Errorf("validating settings: %w", err)
+	}
+
+	wrapped, err := settings.Load()
+	if err != nil {
+		return nil, fmt.Errorf("loading source: %w", err)
+	}
+
+	return &Source{
+		load:   settings.Load,
+		paths:  settings.Paths,
+		source: wrapped,
+		values: snapshot(wrapped),
+	}, nil
+}
+
+// String returns the string of the wrapped source,
+// for example 'TOML file config.toml'.
+func (s *Source) String() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.source.String()
+}
+
+// Get returns the value of the key from the wrapped source.
+func (s *Source) Get(key string) (value string, isSet bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.source.Get(key)
+}
+
+// GetWithError returns the value of the key from the wrapped source
+// using its GetWithError method, or its Get method if it does not
+// implement it, such that errors of the wrapped source are returned.
+func (s *Source) GetWithError(key string) (value string, isSet bool, err error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	errorGetter, ok := s.source.(interface {
+		GetWithError(key string) (value string, isSet bool, err error)
+	})
+	if !ok {
+		value, isSet = s.source.Get(key)
+		return value, isSet, nil
+	}
+	return errorGetter.GetWithError(key)
+}
+
+// KeyTransform transforms the key using the wrapped
+// source KeyTransform method.
+func (s *Source) KeyTransform(key string) string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.source.KeyTransform(key)
+}
+
+// Keys returns the keys of the wrapped source.
+func (s *Source) Keys() (keys []string) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.source.Keys()
+}
+
+// Locate returns the location of the key given using the wrapped
+// source Locate method, or the empty string if the wrapped source
+// does not implement it.
+func (s *Source) Locate(key string) (location string) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	locator, ok := s.source.(interface{ Locate(key string) string })
+	if !ok {
+		return ""
+	}
+	return locator.Locate(key)
+}
+
+// Strict returns the result of the wrapped source Strict method,
+// or false if the wrapped source does not implement it.
+func (s *Source) Strict() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	strict, ok := s.source.(interface{ Strict() bool })
+	return ok && strict.Strict()
+}
+
+// WatchPaths returns the paths given in the settings.
+func (s *Source) WatchPaths() (paths []string) {
+	return s.paths
+}
+
+// Reload calls the load function to re-create the wrapped source,
+// and returns the sorted keys added, removed or with a changed value.
+// If the load function fails, the previous source is kept and
+// the error is returned.
+func (s *Source) Reload() (changedKeys []string, err error) {
+	wrapped, err := s.load()
+	if err != nil {
+		return nil, fmt.Errorf("loading source: %w", err)
+	}
+	values := snapshot(wrapped)
+
+	s.mutex.Lock()
+	oldValues := s.values
+	s.source = wrapped
+	s.values = values
+	s.mutex.Unlock()
+
+	for key, value := range values {
+		oldValue, existed := oldValues[key]
+		if !existed || oldValue != value {
+			changedKeys = append(changedKeys, key)
+		}
+	}
+	for key := range oldValues {
+		if _, exists := values[key]; !exists {
+			changedKeys = append(changedKeys, key)
+		}
+	}
+	sort.Strings(changedKeys)
+	return changedKeys, nil
+}
+
+func snapshot(wrapped Wrapped) (values map[string]string) {
+	keys := wrapped.Keys()
+	values = make(map[string]string, len(keys))
+	for _, key := range keys {
+		value, isSet := wrapped.Get(key)
+		if isSet {
+			values[key] = value
+		}
+	}
+	return values
+}
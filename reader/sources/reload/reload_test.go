@@ -0,0 +1,231 @@
+package reload
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gosettings/reader"
+	"github.com/qdm12/gosettings/reader/sources/dotenv"
+)
+
+type testWrapped struct {
+	keyValue map[string]string
+}
+
+func (t *testWrapped) String() string { return "test" }
+
+func (t *testWrapped) Get(key string) (value string, isSet bool) {
+	value, isSet = t.keyValue[key]
+	return value, isSet
+}
+
+func (t *testWrapped) KeyTransform(key string) string { return key }
+
+func (t *testWrapped) Keys() (keys []string) {
+	for key := range t.keyValue {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+
+	testCases := map[string]struct {
+		settings   Settings
+		errWrapped error
+		errMessage string
+	}{
+		"load_not_set": {
+			settings:   Settings{Paths: []string{"a"}},
+			errWrapped: ErrLoadNotSet,
+			errMessage: "validating settings: load function is not set",
+		},
+		"paths_not_set": {
+			settings: Settings{
+				Load: func() (Wrapped, error) { return nil, nil }, //nolint:nilnil
+			},
+			errWrapped: ErrPathsNotSet,
+			errMessage: "validating settings: paths to watch are not set",
+		},
+		"load_error": {
+			settings: Settings{
+				Load:  func() (Wrapped, error) { return nil, errTest },
+				Paths: []string{"a"},
+			},
+			errWrapped: errTest,
+			errMessage: "loading source: test error",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			source, err := New(testCase.settings)
+
+			if source != nil {
+				t.Error("expected nil source")
+			}
+			if !errors.Is(err, testCase.errWrapped) {
+				t.Errorf("expected error %v to wrap %v", err, testCase.errWrapped)
+			}
+			if err.Error() != testCase.errMessage {
+				t.Errorf("expected error message %q but got %q", testCase.errMessage, err.Error())
+			}
+		})
+	}
+}
+
+func Test_Source_Reload(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+	loads := []struct {
+		keyValue map[string]string
+		err      error
+	}{
+		{keyValue: map[string]string{"A": "1", "B": "2", "C": "3"}},
+		{keyValue: map[string]string{"A": "1", "B": "changed", "D": "4"}},
+		{err: errTest},
+	}
+	loadIndex := 0
+	load := func() (Wrapped, error) {
+		result := loads[loadIndex]
+		loadIndex++
+		if result.err != nil {
+			return nil, result.err
+		}
+		return &testWrapped{keyValue: result.keyValue}, nil
+	}
+
+	source, err := New(Settings{Load: load, Paths: []string{"path"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changedKeys, err := source.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedChangedKeys := []string{"B", "C", "D"}
+	if !reflect.DeepEqual(changedKeys, expectedChangedKeys) {
+		t.Errorf("expected changed keys %v, got %v", expectedChangedKeys, changedKeys)
+	}
+
+	changedKeys, err = source.Reload()
+	if !errors.Is(err, errTest) {
+		t.Errorf("expected error %v to wrap %v", err, errTest)
+	}
+	if changedKeys != nil {
+		t.Errorf("expected no changed keys, got %v", changedKeys)
+	}
+	value, isSet := source.Get("B")
+	if !isSet || value != "changed" {
+		t.Errorf("expected previous source to be kept, got %q (set %t)", value, isSet)
+	}
+}
+
+func Test_Source_Watch(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		pollInterval time.Duration
+		linuxOnly    bool
+	}{
+		"polling": {
+			pollInterval: 10 * time.Millisecond,
+		},
+		"file_system_notifications": {
+			pollInterval: time.Hour,
+			linuxOnly:    true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if testCase.linuxOnly && runtime.GOOS != "linux" {
+				t.Skip("file system notifications are only supported on Linux")
+			}
+
+			path := filepath.Join(t.TempDir(), ".env")
+			err := os.WriteFile(path, []byte("KEY=1\nOTHER=1\n"), 0600)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			source, err := New(Settings{
+				Load: func() (Wrapped, error) {
+					return dotenv.New(dotenv.Settings{Path: path})
+				},
+				Paths: []string{path},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			settingsReader := reader.New(reader.Settings{
+				Sources: []reader.Source{source},
+				Watch: reader.WatchSettings{
+					PollInterval: testCase.pollInterval,
+					Debounce:     time.Millisecond,
+				},
+			})
+			_ = settingsReader.String("KEY")
+
+			ctx, cancel := context.WithCancel(context.Background())
+			changesCh := make(chan []reader.Change)
+			watchErrCh := make(chan error)
+			go func() {
+				watchErrCh <- settingsReader.Watch(ctx, func(changes []reader.Change) {
+					changesCh <- changes
+				})
+			}()
+
+			// Let the watcher set up file system notifications
+			time.Sleep(50 * time.Millisecond)
+			err = os.WriteFile(path, []byte("KEY=2\nOTHER=1\n"), 0600)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			timer := time.NewTimer(5 * time.Second)
+			defer timer.Stop()
+			select {
+			case changes := <-changesCh:
+				expectedChanges := []reader.Change{{
+					Source:     "dotenv file " + path,
+					Key:        "KEY",
+					QueriedKey: "KEY",
+				}}
+				if !reflect.DeepEqual(changes, expectedChanges) {
+					t.Errorf("expected changes %v, got %v", expectedChanges, changes)
+				}
+			case <-timer.C:
+				t.Error("timed out waiting for changes")
+			}
+
+			if value := settingsReader.String("KEY"); value != "2" {
+				t.Errorf("expected reloaded value 2, got %q", value)
+			}
+
+			cancel()
+			err = <-watchErrCh
+			if err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package reload
+
+// Settings contains settings for the reloadable source.
+type Settings struct {
+	// Load creates the wrapped source, reading its files.
+	// It is called once when creating the reloadable source,
+	// and then on each reload. It must be set.
+	Load func() (source Wrapped, err error)
+	// Paths are the file and directory paths read by the
+	// wrapped source, which are watched for changes.
+	// It must not be empty.
+	Paths []string
+}
+
+func (s Settings) validate() (err error) {
+	switch {
+	case s.Load == nil:
+		return ErrLoadNotSet
+	case len(s.Paths) == 0:
+		return ErrPathsNotSet
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package secretsdir
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/exp/maps"
+)
+
+// Source implements a secrets directory settings source,
+// where each file name is a key and each file content is
+// its value, as mounted by Docker in /run/secrets or by
+// Kubernetes in a secret volume.
+// Files in sub-directories have their key made of their
+// path relative to the secrets directory, for example
+// the file db/password has the key db_password.
+// Note all keys are transformed using its KeyTransform method.
+type Source struct {
+	path        string
+	maxFileSize int64
+	handleError func(err error)
+
+	indexOnce sync.Once
+	// keyToPath maps transformed keys to secret
+	// file paths relative to the secrets directory.
+	keyToPath map[string]string
+	// walkErr contains the errors encountered walking
+	// the secrets directory tree, joined together.
+	walkErr error
+}
+
+// New creates a new secrets directory source using the
+// settings given. The directory tree is only walked once, on
+// the first call to Get, Locate or Keys, so secret files added
+// afterwards are never seen. Secret files are however read on
+// each call to Get, so that rotated secrets are read with their
+// new value.
+// Entries with a name starting with `..` are ignored, such as
+// the `..data` symbolic link and timestamped directories created
+// by Kubernetes, but symbolic links to files and directories,
+// such as the ones pointing to `..data/<name>`, are followed.
+// Errors walking the directory tree, such as symbolic link loops,
+// are returned by GetWithError for keys not found.
+// A secrets directory not existing results in no key being set.
+func New(settings Settings) (source *Source) {
+	settings.setDefaults()
+	return &Source{
+		path:        settings.Path,
+		maxFileSize: settings.MaxFileSize,
+		handleError: settings.HandleError,
+	}
+}
+
+func (s *Source) String() string {
+	return "secrets directory " + s.path
+}
+
+var ErrFileTooLarge = errors.New("secret file is too large")
+
+// Get returns the content of the secret file matching the
+// key given, and a boolean `isSet` to indicate if it is set
+// or not. Errors, see GetWithError, are reported using the
+// HandleError settings field if it is set, and the key is
+// then considered as not set.
+func (s *Source) Get(key string) (value string, isSet bool) {
+	value, isSet, err := s.GetWithError(key)
+	if err != nil {
+		if s.handleError != nil {
+			s.handleError(err)
+		}
+		return "", false
+	}
+	return value, isSet
+}
+
+// GetWithError returns the content of the secret file matching
+// the key given, and a boolean `isSet` to indicate if it is set
+// or not. An error is returned if the secret file cannot be read
+// or is larger than the MaxFileSize settings field, or if the key
+// is not found and errors were encountered walking the secrets
+// directory tree, since the key may be in the part not walked.
+func (s *Source) GetWithError(key string) (value string, isSet bool, err error) {
+	s.indexOnce.Do(s.index)
+
+	relativePath, ok := s.keyToPath[key]
+	if !ok {
+		return "", false, s.walkErr
+	}
+
+	value, err = s.readFile(relativePath)
+	if err != nil {
+		return "", false, fmt.Errorf("reading secret file: %w", err)
+	}
+	return value, true, nil
+}
+
+// KeyTransform transforms a generic key or a secret file path
+// relative to the secrets directory to a secrets directory key.
+// It notably:
+// - Changes all characters to be lowercase
+// - Replaces all dashes, dots, spaces and path separators with underscores.
+func (s *Source) KeyTransform(key string) (newKey string) {
+	newKey = strings.ToLower(key)
+	replacer := strings.NewReplacer(
+		"-", "_",
+		".", "_",
+		" ", "_",
+		"/", "_",
+		string(filepath.Separator), "_",
+	)
+	return replacer.Replace(newKey)
+}
+
+// Locate returns the path of the secret file matching the
+// key given, relative to the secrets directory, in the form
+// `file db/password`. It returns the empty string if no
+// secret file matches the key.
+func (s *Source) Locate(key string) (location string) {
+	s.indexOnce.Do(s.index)
+	relativePath, ok := s.keyToPath[key]
+	if !ok {
+		return ""
+	}
+	return "file " + filepath.ToSlash(relativePath)
+}
+
+var ErrSymlinkLoop = errors.New("symbolic link loop")
+
+// index walks the secrets directory tree to map keys to
+// secret file paths relative to the secrets directory.
+func (s *Source) index() {
+	s.keyToPath = make(map[string]string)
+	realPath, err := filepath.EvalSymlinks(s.path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			s.addWalkError(err)
+		}
+		return
+	}
+	s.walk("", map[string]struct{}{realPath: {}})
+}
+
+// walk indexes the secret files in the directory at the path given
+// relative to the secrets directory, following symbolic links to
+// files and directories. The ancestors map contains the real paths
+// of the directories being walked, to detect symbolic link loops.
+func (s *Source) walk(relativeDirectory string, ancestors map[string]struct{}) {
+	entries, err := os.ReadDir(filepath.Join(s.path, relativeDirectory))
+	if err != nil {
+		s.addWalkError(err)
+		return
+	}
+
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), "..") {
+			continue
+		}
+		relativePath := filepath.Join(relativeDirectory, entry.Name())
+		path := filepath.Join(s.path, relativePath)
+
+		mode := entry.Type()
+		if mode&fs.ModeSymlink != 0 {
+			info, err := os.Stat(path)
+			if err != nil {
+				s.addWalkError(err)
+				continue
+			}
+			mode = info.Mode()
+		}
+
+		switch {
+		case mode.IsRegular():
+			s.keyToPath[s.KeyTransform(relativePath)] = relativePath
+		case mode.IsDir():
+			realPath, err := filepath.EvalSymlinks(path)
+			if err != nil {
+				s.addWalkError(err)
+				continue
+			}
+			_, isAncestor := ancestors[realPath]
+			if isAncestor {
+				s.addWalkError(fmt.Errorf("%w: %s points to %s",
+					ErrSymlinkLoop, path, realPath))
+				continue
+			}
+			ancestors[realPath] = struct{}{}
+			s.walk(relativePath, ancestors)
+			delete(ancestors, realPath)
+		}
+	}
+}
+
+func (s *Source) addWalkError(err error) {
+	s.walkErr = errors.Join(s.walkErr, fmt.Errorf("walking secrets directory: %w", err))
+}
+
+func (s *Source) readFile(relativePath string) (content string, err error) {
+	file, err := os.Open(filepath.Join(s.path, relativePath))
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, s.maxFileSize+1))
+	if err != nil {
+		return "", err
+	}
+	if int64(len(data)) > s.maxFileSize {
+		return "", fmt.Errorf("%w: %s exceeds %d bytes",
+			ErrFileTooLarge, file.Name(), s.maxFileSize)
+	}
+	return string(data), nil
+}
+
+// Keys returns the keys of all the secret files
+// found in the secrets directory tree.
+func (s *Source) Keys() (keys []string) {
+	s.indexOnce.Do(s.index)
+	return maps.Keys(s.keyToPath)
+}
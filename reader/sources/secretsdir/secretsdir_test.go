@@ -0,0 +1,158 @@
+package secretsdir
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_Source(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+	files := map[string]string{
+		"api-key":                      "key\n",
+		"db/password":                  "secret",
+		"large":                        "0123456789",
+		"..2024_01_01/token":           "token",
+		"..2024_01_01/kubernetes-only": "hidden",
+		"..2024_01_01/nested/user":     "user",
+	}
+	for path, content := range files {
+		path = filepath.Join(directory, path)
+		err := os.MkdirAll(filepath.Dir(path), 0700)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(path, []byte(content), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	symlinks := map[string]string{
+		"..data": "..2024_01_01",
+		"token":  "..data/token",
+		"nested": "..data/nested",
+	}
+	for name, target := range symlinks {
+		err := os.Symlink(target, filepath.Join(directory, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var handledErrors []error
+	source := New(Settings{
+		Path:        directory,
+		MaxFileSize: 9,
+		HandleError: func(err error) {
+			handledErrors = append(handledErrors, err)
+		},
+	})
+
+	testCases := []struct {
+		key      string
+		value    string
+		isSet    bool
+		location string
+	}{
+		{key: "API_KEY", value: "key\n", isSet: true, location: "file api-key"},
+		{key: "db.password", value: "secret", isSet: true, location: "file db/password"},
+		{key: "token", value: "token", isSet: true, location: "file token"},
+		{key: "nested/user", value: "user", isSet: true, location: "file nested/user"},
+		{key: "kubernetes-only"},
+		{key: "__data"},
+		{key: "missing"},
+	}
+
+	for _, testCase := range testCases {
+		key := source.KeyTransform(testCase.key)
+
+		value, isSet := source.Get(key)
+		if value != testCase.value || isSet != testCase.isSet {
+			t.Errorf("for key %s: expected value %q (set %t), got %q (set %t)",
+				testCase.key, testCase.value, testCase.isSet, value, isSet)
+		}
+
+		location := source.Locate(key)
+		if location != testCase.location {
+			t.Errorf("for key %s: expected location %q, got %q",
+				testCase.key, testCase.location, location)
+		}
+	}
+
+	if len(handledErrors) != 0 {
+		t.Fatalf("expected no handled error, got %v", handledErrors)
+	}
+
+	value, isSet := source.Get("large")
+	if value != "" || isSet {
+		t.Errorf("expected large secret to not be set, got %q (set %t)", value, isSet)
+	}
+	if len(handledErrors) != 1 || !errors.Is(handledErrors[0], ErrFileTooLarge) {
+		t.Errorf("expected a single handled error wrapping %v, got %v",
+			ErrFileTooLarge, handledErrors)
+	}
+}
+
+func Test_Source_directoryNotExist(t *testing.T) {
+	t.Parallel()
+
+	var handledErrors []error
+	source := New(Settings{
+		Path: filepath.Join(t.TempDir(), "missing"),
+		HandleError: func(err error) {
+			handledErrors = append(handledErrors, err)
+		},
+	})
+
+	value, isSet := source.Get("key")
+
+	if value != "" || isSet {
+		t.Errorf("expected key to not be set, got %q (set %t)", value, isSet)
+	}
+	if len(handledErrors) != 0 {
+		t.Errorf("expected no handled error, got %v", handledErrors)
+	}
+}
+
+func Test_Source_symlinkLoop(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+	err := os.MkdirAll(filepath.Join(directory, "a"), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(directory, "a", "file"), []byte("value"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Symlink("..", filepath.Join(directory, "a", "loop"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source := New(Settings{Path: directory})
+
+	value, isSet, err := source.GetWithError("a_file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "value" || !isSet {
+		t.Errorf("expected value %q to be set, got %q (set %t)", "value", value, isSet)
+	}
+
+	_, isSet, err = source.GetWithError("missing")
+	if isSet || !errors.Is(err, ErrSymlinkLoop) {
+		t.Errorf("expected key to not be set and error %v to wrap %v",
+			err, ErrSymlinkLoop)
+	}
+
+	// No HandleError set, so the error is not reported by Get.
+	_, isSet = source.Get("missing")
+	if isSet {
+		t.Error("expected key to not be set")
+	}
+}
@@ -0,0 +1,29 @@
+package secretsdir
+
+import (
+	"github.com/qdm12/gosettings"
+)
+
+// Settings contains settings for the secrets directory source.
+type Settings struct {
+	// Path is the path to the secrets directory to read.
+	// It defaults to "/run/secrets".
+	Path string
+	// MaxFileSize is the maximum size in bytes of a secret file.
+	// Reading a secret file larger than this size is an error.
+	// It defaults to 1MiB.
+	MaxFileSize int64
+	// HandleError, if set, is called by the Get method with the
+	// errors returned by the GetWithError method, and the secret
+	// key concerned is then considered as not set. The reader uses
+	// GetWithError, so these errors are returned by its methods
+	// returning an error regardless of this field.
+	// It defaults to nil.
+	HandleError func(err error)
+}
+
+func (s *Settings) setDefaults() {
+	s.Path = gosettings.DefaultComparable(s.Path, "/run/secrets")
+	const defaultMaxFileSize = 1 << 20
+	s.MaxFileSize = gosettings.DefaultComparable(s.MaxFileSize, defaultMaxFileSize)
+}
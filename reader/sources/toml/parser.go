@@ -0,0 +1,523 @@
+package toml
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/gosettings/internal/flatten"
+)
+
+var (
+	ErrKeyExpected        = errors.New("expected a key")
+	ErrEqualSignExpected  = errors.New("expected an equal sign after key")
+	ErrValueExpected      = errors.New("expected a value")
+	ErrTrailingCharacters = errors.New("unexpected characters")
+	ErrTableHeader        = errors.New("table header is not valid")
+	ErrKeyDuplicate       = errors.New("duplicate key")
+	ErrKeyRedefined       = errors.New("key is already defined")
+	ErrTableRedefined     = errors.New("table is already defined")
+	ErrArraySyntax        = errors.New("unexpected character in array")
+	ErrInlineTableSyntax  = errors.New("unexpected character in inline table")
+)
+
+// table is a TOML table, where values are either strings,
+// []any arrays, *table tables or *tableArray arrays of tables.
+type table struct {
+	values map[string]any
+	// explicit is true if the table is defined by a table header.
+	explicit bool
+	// dotted is true if the table is defined by dotted keys.
+	dotted bool
+	// inline is true if the table is an inline table,
+	// which cannot be modified once defined.
+	inline bool
+}
+
+func newTable() *table {
+	return &table{values: make(map[string]any)}
+}
+
+type tableArray struct {
+	tables []*table
+}
+
+type parser struct {
+	content string
+	offset  int
+	// line is the 0-indexed line number of the current offset.
+	line int
+	// lineStart is the offset of the start of the current line.
+	lineStart   int
+	root        *table
+	current     *table
+	currentPath []string
+	keyToLine   map[string]int
+}
+
+// parse parses the TOML content given, and returns the root table
+// as a map where values are either strings, []any slices or
+// map[string]any maps, and a map of flattened keys to the line
+// number where their value is defined.
+func parse(content string) (root map[string]any,
+	keyToLine map[string]int, err error) {
+	p := &parser{
+		content:   strings.ReplaceAll(content, "\r\n", "\n"),
+		root:      newTable(),
+		keyToLine: make(map[string]int),
+	}
+	p.current = p.root
+	p.currentPath = []string{} // non-nil to record line numbers
+
+	for {
+		p.skipBlank()
+		if p.offset == len(p.content) {
+			break
+		}
+
+		if p.peek() == '[' {
+			err = p.parseTableHeader()
+		} else {
+			err = p.parseKeyValue(p.current, p.currentPath)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		err = p.expectLineEnd()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return p.root.toMap(), p.keyToLine, nil
+}
+
+const endOfInput = 0
+
+func (p *parser) peek() byte {
+	if p.offset >= len(p.content) {
+		return endOfInput
+	}
+	return p.content[p.offset]
+}
+
+func (p *parser) advance() {
+	if p.content[p.offset] == '\n' {
+		p.line++
+		p.lineStart = p.offset + 1
+	}
+	p.offset++
+}
+
+func (p *parser) skipSpaces() {
+	for c := p.peek(); c == ' ' || c == '\t'; c = p.peek() {
+		p.advance()
+	}
+}
+
+func (p *parser) skipComment() {
+	if p.peek() != '#' {
+		return
+	}
+	for c := p.peek(); c != '\n' && c != endOfInput; c = p.peek() {
+		p.advance()
+	}
+}
+
+// skipBlank skips spaces, tabs, comments and line feeds.
+func (p *parser) skipBlank() {
+	for {
+		p.skipSpaces()
+		p.skipComment()
+		if p.peek() != '\n' {
+			return
+		}
+		p.advance()
+	}
+}
+
+func (p *parser) expectLineEnd() (err error) {
+	p.skipSpaces()
+	p.skipComment()
+	switch p.peek() {
+	case endOfInput:
+		return nil
+	case '\n':
+		p.advance()
+		return nil
+	}
+	end := strings.IndexByte(p.content[p.offset:], '\n')
+	if end == -1 {
+		end = len(p.content) - p.offset
+	}
+	return p.errorf(fmt.Errorf("%w: %s", ErrTrailingCharacters,
+		p.content[p.offset:p.offset+end]))
+}
+
+// errorf returns the error given wrapped with the line and column
+// of the current offset.
+func (p *parser) errorf(err error) error {
+	return p.errorAt(p.line, p.offset-p.lineStart, err)
+}
+
+// errorAt returns the error given wrapped with the line and column
+// computed from the given 0-indexed line and column.
+func (p *parser) errorAt(line, column int, err error) error {
+	return fmt.Errorf("line %d column %d: %w", line+1, column+1, err)
+}
+
+func (p *parser) parseTableHeader() (err error) {
+	headerLine := p.line
+	p.advance() // [
+	isArray := p.peek() == '['
+	if isArray {
+		p.advance()
+	}
+
+	keysLine, keysColumn := p.line, p.offset-p.lineStart
+	keys, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+
+	closing := "]"
+	if isArray {
+		closing = "]]"
+	}
+	if !strings.HasPrefix(p.content[p.offset:], closing) {
+		return p.errorf(fmt.Errorf("%w: expected %s", ErrTableHeader, closing))
+	}
+	for range closing {
+		p.advance()
+	}
+
+	var table *table
+	var path []string
+	if isArray {
+		table, path, err = p.appendArrayTable(keys)
+	} else {
+		table, path, err = p.defineTable(keys)
+	}
+	if err != nil {
+		return p.errorAt(keysLine, keysColumn, err)
+	}
+
+	p.current = table
+	p.currentPath = path
+	p.keyToLine[flatKey(path)] = headerLine + 1
+	return nil
+}
+
+// parseKey parses a key made of one or more simple keys separated by dots.
+func (p *parser) parseKey() (keys []string, err error) {
+	for {
+		p.skipSpaces()
+		key, err := p.parseSimpleKey()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+		p.skipSpaces()
+		if p.peek() != '.' {
+			return keys, nil
+		}
+		p.advance()
+	}
+}
+
+func (p *parser) parseSimpleKey() (key string, err error) {
+	switch p.peek() {
+	case '"':
+		return p.parseBasicString()
+	case '\'':
+		return p.parseLiteralString()
+	}
+
+	start := p.offset
+	for isBareKeyCharacter(p.peek()) {
+		p.advance()
+	}
+	if p.offset == start {
+		return "", p.errorf(ErrKeyExpected)
+	}
+	return p.content[start:p.offset], nil
+}
+
+func isBareKeyCharacter(c byte) (ok bool) {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') ||
+		(c >= '0' && c <= '9') || c == '_' || c == '-'
+}
+
+// navigate returns the table found at the given keys starting from the
+// root table, creating implicit tables as needed, and its flattened path
+// components, including indexes of arrays of tables.
+func (p *parser) navigate(keys []string) (current *table, path []string, err error) {
+	current = p.root
+	for _, key := range keys {
+		path = append(path, key)
+		child, exists := current.values[key]
+		if !exists {
+			childTable := newTable()
+			current.values[key] = childTable
+			current = childTable
+			continue
+		}
+
+		switch typedChild := child.(type) {
+		case *table:
+			if typedChild.inline {
+				return nil, nil, fmt.Errorf("%w: %s", ErrKeyRedefined, strings.Join(keys, "."))
+			}
+			current = typedChild
+		case *tableArray:
+			lastIndex := len(typedChild.tables) - 1
+			path = append(path, strconv.Itoa(lastIndex))
+			current = typedChild.tables[lastIndex]
+		default:
+			return nil, nil, fmt.Errorf("%w: %s", ErrKeyRedefined, strings.Join(keys, "."))
+		}
+	}
+	return current, path, nil
+}
+
+func (p *parser) defineTable(keys []string) (defined *table, path []string, err error) {
+	parent, path, err := p.navigate(keys[:len(keys)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lastKey := keys[len(keys)-1]
+	path = append(path, lastKey)
+	existing, exists := parent.values[lastKey]
+	if !exists {
+		defined = newTable()
+		defined.explicit = true
+		parent.values[lastKey] = defined
+		return defined, path, nil
+	}
+
+	existingTable, ok := existing.(*table)
+	if !ok || existingTable.explicit || existingTable.dotted || existingTable.inline {
+		return nil, nil, fmt.Errorf("%w: %s", ErrTableRedefined, strings.Join(keys, "."))
+	}
+	existingTable.explicit = true
+	return existingTable, path, nil
+}
+
+func (p *parser) appendArrayTable(keys []string) (appended *table, path []string, err error) {
+	parent, path, err := p.navigate(keys[:len(keys)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lastKey := keys[len(keys)-1]
+	existing, exists := parent.values[lastKey]
+	if !exists {
+		existing = &tableArray{}
+		parent.values[lastKey] = existing
+	}
+	array, ok := existing.(*tableArray)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrKeyRedefined, strings.Join(keys, "."))
+	}
+
+	appended = newTable()
+	appended.explicit = true
+	array.tables = append(array.tables, appended)
+	path = append(path, lastKey, strconv.Itoa(len(array.tables)-1))
+	return appended, path, nil
+}
+
+// parseKeyValue parses a key value pair and sets it in the table given.
+// If path is not nil, the line of the key value pair is recorded
+// for the flattened key made from the path and the key.
+func (p *parser) parseKeyValue(current *table, path []string) (err error) {
+	keyLine, keyColumn := p.line, p.offset-p.lineStart
+	keys, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+
+	p.skipSpaces()
+	if p.peek() != '=' {
+		return p.errorf(ErrEqualSignExpected)
+	}
+	p.advance()
+	p.skipSpaces()
+
+	value, err := p.parseValue()
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys[:len(keys)-1] {
+		child, exists := current.values[key]
+		if !exists {
+			childTable := newTable()
+			childTable.dotted = true
+			current.values[key] = childTable
+			current = childTable
+			continue
+		}
+		childTable, ok := child.(*table)
+		if !ok || childTable.inline || childTable.explicit {
+			return p.errorAt(keyLine, keyColumn,
+				fmt.Errorf("%w: %s", ErrKeyRedefined, strings.Join(keys, ".")))
+		}
+		current = childTable
+	}
+
+	lastKey := keys[len(keys)-1]
+	_, exists := current.values[lastKey]
+	if exists {
+		return p.errorAt(keyLine, keyColumn,
+			fmt.Errorf("%w: %s", ErrKeyDuplicate, strings.Join(keys, ".")))
+	}
+	current.values[lastKey] = value
+
+	if path != nil {
+		keyPath := make([]string, 0, len(path)+len(keys))
+		keyPath = append(keyPath, path...)
+		keyPath = append(keyPath, keys...)
+		p.keyToLine[flatKey(keyPath)] = keyLine + 1
+	}
+	return nil
+}
+
+func flatKey(path []string) (key string) {
+	return flatten.NormalizeKey(strings.Join(path, "_"))
+}
+
+func (p *parser) parseValue() (value any, err error) {
+	switch p.peek() {
+	case '"':
+		if strings.HasPrefix(p.content[p.offset:], `"""`) {
+			return p.parseMultiLineBasicString()
+		}
+		return p.parseBasicString()
+	case '\'':
+		if strings.HasPrefix(p.content[p.offset:], "'''") {
+			return p.parseMultiLineLiteralString()
+		}
+		return p.parseLiteralString()
+	case '[':
+		return p.parseArray()
+	case '{':
+		return p.parseInlineTable()
+	case endOfInput, '\n', '#':
+		return nil, p.errorf(ErrValueExpected)
+	default:
+		return p.parseScalar()
+	}
+}
+
+func (p *parser) parseArray() (array []any, err error) {
+	p.advance() // [
+	array = []any{}
+	for {
+		p.skipBlank()
+		if p.peek() == ']' {
+			p.advance()
+			return array, nil
+		}
+
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		array = append(array, value)
+
+		p.skipBlank()
+		switch c := p.peek(); c {
+		case ',':
+			p.advance()
+		case ']':
+			p.advance()
+			return array, nil
+		case endOfInput:
+			return nil, p.errorf(fmt.Errorf("%w: end of input instead of ',' or ']'",
+				ErrArraySyntax))
+		default:
+			return nil, p.errorf(fmt.Errorf("%w: %q instead of ',' or ']'",
+				ErrArraySyntax, c))
+		}
+	}
+}
+
+func (p *parser) parseInlineTable() (inlineTable *table, err error) {
+	p.advance() // {
+	inlineTable = newTable()
+	p.skipSpaces()
+	if p.peek() == '}' {
+		p.advance()
+		inlineTable.inline = true
+		return inlineTable, nil
+	}
+
+	for {
+		err = p.parseKeyValue(inlineTable, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipSpaces()
+		switch c := p.peek(); c {
+		case ',':
+			p.advance()
+			p.skipSpaces()
+		case '}':
+			p.advance()
+			markInline(inlineTable)
+			return inlineTable, nil
+		case endOfInput, '\n':
+			return nil, p.errorf(fmt.Errorf("%w: line end instead of ',' or '}'",
+				ErrInlineTableSyntax))
+		default:
+			return nil, p.errorf(fmt.Errorf("%w: %q instead of ',' or '}'",
+				ErrInlineTableSyntax, c))
+		}
+	}
+}
+
+// markInline marks the table given and its sub-tables
+// defined with dotted keys as inline tables.
+func markInline(t *table) {
+	t.inline = true
+	for _, value := range t.values {
+		child, ok := value.(*table)
+		if ok {
+			markInline(child)
+		}
+	}
+}
+
+func (t *table) toMap() (m map[string]any) {
+	m = make(map[string]any, len(t.values))
+	for key, value := range t.values {
+		m[key] = toAny(value)
+	}
+	return m
+}
+
+func toAny(value any) (converted any) {
+	switch typedValue := value.(type) {
+	case *table:
+		return typedValue.toMap()
+	case *tableArray:
+		array := make([]any, len(typedValue.tables))
+		for i, element := range typedValue.tables {
+			array[i] = element.toMap()
+		}
+		return array
+	case []any:
+		array := make([]any, len(typedValue))
+		for i, element := range typedValue {
+			array[i] = toAny(element)
+		}
+		return array
+	default:
+		return value
+	}
+}
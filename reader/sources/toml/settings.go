@@ -0,0 +1,16 @@
+package toml
+
+import (
+	"github.com/qdm12/gosettings"
+)
+
+// Settings contains settings for the TOML file source.
+type Settings struct {
+	// Path is the path to the TOML file to read.
+	// It defaults to "config.toml".
+	Path string
+}
+
+func (s *Settings) setDefaults() {
+	s.Path = gosettings.DefaultComparable(s.Path, "config.toml")
+}
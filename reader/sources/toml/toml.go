@@ -0,0 +1,118 @@
+package toml
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/qdm12/gosettings/internal/flatten"
+	"golang.org/x/exp/maps"
+)
+
+// Source implements a TOML file settings source.
+// Tables and dotted keys are flattened into keys joined with
+// underscores, such that `[server]` with `listen_address = ":8000"`
+// is accessible with the key SERVER_LISTEN_ADDRESS.
+// Note all keys are transformed using its KeyTransform method.
+type Source struct {
+	path       string
+	keyToValue map[string]string
+	keyToLine  map[string]int
+}
+
+// New creates a new TOML file source by reading and parsing
+// the TOML file at the path given in settings.
+//
+// Values are converted to strings such that:
+//   - strings are kept as they are
+//   - integers are converted to their base 10 representation
+//   - floats and booleans are kept as written, without underscores
+//   - offset date-times are converted to RFC 3339 strings, and local
+//     date-times, local dates and local times are kept without offset,
+//     such that date-times and dates can be read with the Time methods
+//   - arrays of non-table and non-array values are converted to
+//     comma separated values, to be used with the CSV methods,
+//     and cannot contain elements with a comma
+//   - arrays of tables and other arrays are flattened using the
+//     element index as key, for example SERVERS_0_ADDRESS.
+//
+// An error is returned if the file cannot be read, if its content
+// is malformed, with the line and column in the error message,
+// or if two different keys result in the same flattened key.
+func New(settings Settings) (source *Source, err error) {
+	settings.setDefaults()
+
+	content, err := os.ReadFile(settings.Path)
+	if err != nil {
+		return nil, fmt.Errorf("reading TOML file: %w", err)
+	}
+
+	root, keyToLine, err := parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("parsing TOML file %s: %w", settings.Path, err)
+	}
+
+	keyToValue, err := flatten.Flatten(root)
+	if err != nil {
+		return nil, fmt.Errorf("flattening TOML file %s: %w", settings.Path, err)
+	}
+
+	return &Source{
+		path:       settings.Path,
+		keyToValue: keyToValue,
+		keyToLine:  keyToLine,
+	}, nil
+}
+
+// ErrKeyConflict is returned when two different keys
+// result in the same flattened key.
+var ErrKeyConflict = flatten.ErrKeyConflict
+
+// ErrArrayElementComma is returned if an element of an array
+// converted to comma separated values contains a comma.
+var ErrArrayElementComma = flatten.ErrArrayElementComma
+
+func (s *Source) String() string {
+	return "TOML file " + s.path
+}
+
+// Get returns the value of the flattened TOML key
+// given, and a boolean `isSet` to indicate if it is
+// set or not.
+func (s *Source) Get(key string) (value string, isSet bool) {
+	value, isSet = s.keyToValue[key]
+	return value, isSet
+}
+
+// KeyTransform transforms a generic key to a flattened
+// TOML key. It notably:
+// - Changes all characters to be uppercase
+// - Replaces all dashes, dots and spaces with underscores.
+func (s *Source) KeyTransform(key string) (newKey string) {
+	return flatten.NormalizeKey(key)
+}
+
+// Keys returns all the keys set in the TOML file.
+func (s *Source) Keys() (keys []string) {
+	return maps.Keys(s.keyToValue)
+}
+
+// Locate returns the line where the value for the given
+// flattened key is defined in the TOML file, in the form
+// `line 5`. It returns the empty string if the key is not
+// found in the TOML file.
+func (s *Source) Locate(key string) (location string) {
+	for {
+		line, ok := s.keyToLine[key]
+		if ok {
+			return fmt.Sprintf("line %d", line)
+		}
+		// The key can be an element of an array or inline table
+		// defined at a parent key.
+		underscoreIndex := strings.LastIndexByte(key, '_')
+		if underscoreIndex == -1 {
+			return ""
+		}
+		key = key[:underscoreIndex]
+	}
+}
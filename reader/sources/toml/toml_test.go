@@ -0,0 +1,408 @@
+package toml
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/qdm12/gosettings/internal/flatten"
+)
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "config.toml")
+		const content = "log_level = \"info\"\n\n[server]\nlisten-address = \":8000\"\nports = [1, 2]\n"
+		err := os.WriteFile(path, []byte(content), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		source, err := New(Settings{Path: path})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expectedSource := &Source{
+			path: path,
+			keyToValue: map[string]string{
+				"LOG_LEVEL":             "info",
+				"SERVER_LISTEN_ADDRESS": ":8000",
+				"SERVER_PORTS":          "1,2",
+			},
+			keyToLine: map[string]int{
+				"LOG_LEVEL":             1,
+				"SERVER":                3,
+				"SERVER_LISTEN_ADDRESS": 4,
+				"SERVER_PORTS":          5,
+			},
+		}
+		if !reflect.DeepEqual(source, expectedSource) {
+			t.Errorf("expected source %#v, got %#v", expectedSource, source)
+		}
+
+		key := source.KeyTransform("server.listen-address")
+		value, isSet := source.Get(key)
+		if !isSet || value != ":8000" {
+			t.Errorf("expected value :8000 to be set, got %q (set %t)", value, isSet)
+		}
+
+		location := source.Locate(key)
+		if location != "line 4" {
+			t.Errorf("expected location %q, got %q", "line 4", location)
+		}
+	})
+
+	t.Run("parse_error", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "config.toml")
+		err := os.WriteFile(path, []byte("a = 1\nb = \n"), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = New(Settings{Path: path})
+		errRegex := regexp.MustCompile(`^parsing TOML file /.+/config\.toml: ` +
+			`line 2 column 5: expected a value$`)
+		if err == nil || !errRegex.MatchString(err.Error()) {
+			t.Errorf("expected error to match %s, got %v", errRegex, err)
+		}
+	})
+
+	t.Run("array_element_with_comma", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "config.toml")
+		err := os.WriteFile(path, []byte("names = [\"a\", \"b,c\"]\n"), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = New(Settings{Path: path})
+		if !errors.Is(err, ErrArrayElementComma) {
+			t.Errorf("expected error %v to wrap %v", err, ErrArrayElementComma)
+		}
+		errRegex := regexp.MustCompile(`^flattening TOML file /.+/config\.toml: ` +
+			`array element contains a comma: names\[1\] is "b,c"$`)
+		if err == nil || !errRegex.MatchString(err.Error()) {
+			t.Errorf("expected error to match %s, got %v", errRegex, err)
+		}
+	})
+
+	t.Run("file_not_found", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "config.toml")
+		_, err := New(Settings{Path: path})
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected error %v to wrap %v", err, os.ErrNotExist)
+		}
+	})
+}
+
+func Test_Source_Locate(t *testing.T) {
+	t.Parallel()
+
+	source := &Source{
+		keyToLine: map[string]int{
+			"SERVER":       2,
+			"SERVER_PORTS": 3,
+		},
+	}
+
+	testCases := map[string]struct {
+		key      string
+		location string
+	}{
+		"exact_key": {
+			key:      "SERVER_PORTS",
+			location: "line 3",
+		},
+		"array_element": {
+			key:      "SERVER_PORTS_0_NUMBER",
+			location: "line 3",
+		},
+		"parent_table": {
+			key:      "SERVER_ADDRESS",
+			location: "line 2",
+		},
+		"not_found": {
+			key: "CLIENT",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			location := source.Locate(testCase.key)
+
+			if location != testCase.location {
+				t.Errorf("expected location %q, got %q", testCase.location, location)
+			}
+		})
+	}
+}
+
+func Test_parse(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		content    string
+		keyToValue map[string]string
+		errWrapped error
+		errMessage string
+	}{
+		"empty": {
+			keyToValue: map[string]string{},
+		},
+		"comments_only": {
+			content:    "# comment\n\n  # other comment\r\n",
+			keyToValue: map[string]string{},
+		},
+		"tables_and_dotted_keys": {
+			content: `
+title = "example" # comment
+site."google.com" = true
+
+[server]
+listen_address = ":8000"
+tls.enabled = false
+
+[server.log]
+level = 'debug'
+
+[ database . "main" ]
+host = "localhost"
+`,
+			keyToValue: map[string]string{
+				"TITLE":                 "example",
+				"SITE_GOOGLE_COM":       "true",
+				"SERVER_LISTEN_ADDRESS": ":8000",
+				"SERVER_TLS_ENABLED":    "false",
+				"SERVER_LOG_LEVEL":      "debug",
+				"DATABASE_MAIN_HOST":    "localhost",
+			},
+		},
+		"arrays": {
+			content: `
+integers = [1, 2, 3]
+strings = ["a", 'b', """c"""]
+empty = []
+multi_line = [
+  "first", # comment
+  "second",
+]
+nested = [[1, 2], ["a"]]
+tables = [{ name = "a" }, { name = "b", port = 80 }]
+`,
+			keyToValue: map[string]string{
+				"INTEGERS":      "1,2,3",
+				"STRINGS":       "a,b,c",
+				"EMPTY":         "",
+				"MULTI_LINE":    "first,second",
+				"NESTED_0":      "1,2",
+				"NESTED_1":      "a",
+				"TABLES_0_NAME": "a",
+				"TABLES_1_NAME": "b",
+				"TABLES_1_PORT": "80",
+			},
+		},
+		"inline_tables": {
+			content: `server = { address = "1.2.3.4", tls = { enabled = true } }`,
+			keyToValue: map[string]string{
+				"SERVER_ADDRESS":     "1.2.3.4",
+				"SERVER_TLS_ENABLED": "true",
+			},
+		},
+		"array_of_tables": {
+			content: `
+[[servers]]
+address = "1.2.3.4"
+
+[servers.tls]
+enabled = true
+
+[[servers]]
+address = "5.6.7.8"
+`,
+			keyToValue: map[string]string{
+				"SERVERS_0_ADDRESS":     "1.2.3.4",
+				"SERVERS_0_TLS_ENABLED": "true",
+				"SERVERS_1_ADDRESS":     "5.6.7.8",
+			},
+		},
+		"strings": {
+			content: `
+basic = "tab\there \"quoted\" \u00e9\U0001F600 \\"
+literal = 'C:\path'
+multi_line_basic = """
+first \
+   second
+third"""""
+multi_line_literal = '''
+raw \n '' text'''
+`,
+			keyToValue: map[string]string{
+				"BASIC":              "tab\there \"quoted\" é😀 \\",
+				"LITERAL":            `C:\path`,
+				"MULTI_LINE_BASIC":   "first second\nthird\"\"",
+				"MULTI_LINE_LITERAL": `raw \n '' text`,
+			},
+		},
+		"numbers": {
+			content: `
+decimal = +1_000
+negative = -17
+hexadecimal = 0xDEAD_beef
+octal = 0o755
+binary = 0b1101
+float = 3.14_15
+exponent = -5e+22
+infinity = -inf
+not_a_number = nan
+`,
+			keyToValue: map[string]string{
+				"DECIMAL":      "1000",
+				"NEGATIVE":     "-17",
+				"HEXADECIMAL":  "3735928559",
+				"OCTAL":        "493",
+				"BINARY":       "13",
+				"FLOAT":        "3.1415",
+				"EXPONENT":     "-5e+22",
+				"INFINITY":     "-inf",
+				"NOT_A_NUMBER": "nan",
+			},
+		},
+		"date_times": {
+			content: `
+offset = 1979-05-27T07:32:00.5-07:00
+utc = 1979-05-27 07:32:00z
+local_date_time = 1979-05-27T07:32:00
+local_date = 1979-05-27
+local_time = 07:32:00.999
+`,
+			keyToValue: map[string]string{
+				"OFFSET":          "1979-05-27T07:32:00.5-07:00",
+				"UTC":             "1979-05-27T07:32:00Z",
+				"LOCAL_DATE_TIME": "1979-05-27T07:32:00",
+				"LOCAL_DATE":      "1979-05-27",
+				"LOCAL_TIME":      "07:32:00.999",
+			},
+		},
+		"key_expected": {
+			content:    "= 1",
+			errWrapped: ErrKeyExpected,
+			errMessage: "line 1 column 1: expected a key",
+		},
+		"equal_sign_expected": {
+			content:    "a 1",
+			errWrapped: ErrEqualSignExpected,
+			errMessage: "line 1 column 3: expected an equal sign after key",
+		},
+		"value_expected": {
+			content:    "a =\nb = 1",
+			errWrapped: ErrValueExpected,
+			errMessage: "line 1 column 4: expected a value",
+		},
+		"trailing_characters": {
+			content:    `a = "x" y`,
+			errWrapped: ErrTrailingCharacters,
+			errMessage: "line 1 column 9: unexpected characters: y",
+		},
+		"duplicate_key": {
+			content:    "a = 1\nb = 2\na = 3",
+			errWrapped: ErrKeyDuplicate,
+			errMessage: "line 3 column 1: duplicate key: a",
+		},
+		"table_redefined": {
+			content:    "[a]\nx = 1\n[a]\ny = 2",
+			errWrapped: ErrTableRedefined,
+			errMessage: "line 3 column 2: table is already defined: a",
+		},
+		"inline_table_extended": {
+			content:    "a = { x = 1 }\na.y = 2",
+			errWrapped: ErrKeyRedefined,
+			errMessage: "line 2 column 1: key is already defined: a.y",
+		},
+		"string_not_closed": {
+			content:    "a = \"x\nb = 1",
+			errWrapped: ErrStringNotClosed,
+			errMessage: "line 1 column 5: string is not closed",
+		},
+		"invalid_escape": {
+			content:    `a = "\q"`,
+			errWrapped: ErrEscapeSequence,
+			errMessage: `line 1 column 6: escape sequence is not valid: \q`,
+		},
+		"invalid_integer": {
+			content:    "a = 1__0",
+			errWrapped: ErrInteger,
+			errMessage: "line 1 column 5: integer is not valid: 1__0",
+		},
+		"leading_zero": {
+			content:    "a = 01",
+			errWrapped: ErrInteger,
+			errMessage: "line 1 column 5: integer is not valid: 01",
+		},
+		"invalid_float": {
+			content:    "a = 1.",
+			errWrapped: ErrFloat,
+			errMessage: "line 1 column 5: float is not valid: 1.",
+		},
+		"invalid_date_time": {
+			content:    "a = 1979-13-27",
+			errWrapped: ErrDateTime,
+			errMessage: "line 1 column 5: date-time is not valid: 1979-13-27",
+		},
+		"invalid_value": {
+			content:    "a = yes",
+			errWrapped: ErrValueNotValid,
+			errMessage: "line 1 column 5: value is not valid: yes",
+		},
+		"array_not_closed": {
+			content:    "a = [1, 2",
+			errWrapped: ErrArraySyntax,
+		},
+		"inline_table_syntax": {
+			content:    "a = { x = 1 y = 2 }",
+			errWrapped: ErrInlineTableSyntax,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			root, _, err := parse(testCase.content)
+
+			if !errors.Is(err, testCase.errWrapped) {
+				t.Errorf("expected error %v to wrap %v", err, testCase.errWrapped)
+			}
+			if testCase.errWrapped != nil {
+				if testCase.errMessage != "" && err.Error() != testCase.errMessage {
+					t.Errorf("expected error message %q, got %q",
+						testCase.errMessage, err.Error())
+				}
+				return
+			}
+
+			keyToValue, err := flatten.Flatten(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(keyToValue, testCase.keyToValue) {
+				t.Errorf("expected %#v, got %#v", testCase.keyToValue, keyToValue)
+			}
+		})
+	}
+}
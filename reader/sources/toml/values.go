@@ -0,0 +1,372 @@
+package toml
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrStringNotClosed = errors.New("string is not closed")
+	ErrEscapeSequence  = errors.New("escape sequence is not valid")
+	ErrValueNotValid   = errors.New("value is not valid")
+	ErrInteger         = errors.New("integer is not valid")
+	ErrFloat           = errors.New("float is not valid")
+	ErrDateTime        = errors.New("date-time is not valid")
+)
+
+func (p *parser) parseBasicString() (value string, err error) {
+	startLine, startColumn := p.line, p.offset-p.lineStart
+	p.advance() // "
+	var builder strings.Builder
+	for {
+		switch c := p.peek(); c {
+		case endOfInput, '\n':
+			return "", p.errorAt(startLine, startColumn, ErrStringNotClosed)
+		case '"':
+			p.advance()
+			return builder.String(), nil
+		case '\\':
+			err = p.parseEscape(&builder)
+			if err != nil {
+				return "", err
+			}
+		default:
+			builder.WriteByte(c)
+			p.advance()
+		}
+	}
+}
+
+func (p *parser) parseMultiLineBasicString() (value string, err error) {
+	startLine, startColumn := p.line, p.offset-p.lineStart
+	p.offset += len(`"""`)
+	if p.peek() == '\n' { // newline right after the delimiter is trimmed
+		p.advance()
+	}
+
+	var builder strings.Builder
+	for {
+		switch c := p.peek(); c {
+		case endOfInput:
+			return "", p.errorAt(startLine, startColumn, ErrStringNotClosed)
+		case '"':
+			closed := p.parseMultiLineClosing(&builder, '"')
+			if closed {
+				return builder.String(), nil
+			}
+		case '\\':
+			if p.isLineEndingBackslash() {
+				p.advance()
+				for c := p.peek(); c == ' ' || c == '\t' || c == '\n'; c = p.peek() {
+					p.advance()
+				}
+				continue
+			}
+			err = p.parseEscape(&builder)
+			if err != nil {
+				return "", err
+			}
+		default:
+			builder.WriteByte(c)
+			p.advance()
+		}
+	}
+}
+
+// isLineEndingBackslash returns true if the backslash at the current
+// offset is only followed by spaces and tabs until the end of the line.
+func (p *parser) isLineEndingBackslash() (ok bool) {
+	rest := strings.TrimLeft(p.content[p.offset+1:], " \t")
+	return strings.HasPrefix(rest, "\n")
+}
+
+// parseMultiLineClosing handles a quote character found in a multi-line
+// string, and returns true if it is the closing delimiter. Up to two
+// quote characters can be placed right before the closing delimiter.
+func (p *parser) parseMultiLineClosing(builder *strings.Builder, quote byte) (closed bool) {
+	quotes := 0
+	for p.offset+quotes < len(p.content) && p.content[p.offset+quotes] == quote {
+		quotes++
+	}
+
+	const delimiterLength, maxExtraQuotes = 3, 2
+	if quotes < delimiterLength {
+		for i := 0; i < quotes; i++ {
+			builder.WriteByte(quote)
+			p.advance()
+		}
+		return false
+	}
+
+	extraQuotes := min(quotes-delimiterLength, maxExtraQuotes)
+	for i := 0; i < extraQuotes; i++ {
+		builder.WriteByte(quote)
+	}
+	p.offset += extraQuotes + delimiterLength
+	return true
+}
+
+func (p *parser) parseLiteralString() (value string, err error) {
+	startLine, startColumn := p.line, p.offset-p.lineStart
+	p.advance() // '
+	start := p.offset
+	for {
+		switch p.peek() {
+		case endOfInput, '\n':
+			return "", p.errorAt(startLine, startColumn, ErrStringNotClosed)
+		case '\'':
+			value = p.content[start:p.offset]
+			p.advance()
+			return value, nil
+		default:
+			p.advance()
+		}
+	}
+}
+
+func (p *parser) parseMultiLineLiteralString() (value string, err error) {
+	startLine, startColumn := p.line, p.offset-p.lineStart
+	p.offset += len("'''")
+	if p.peek() == '\n' { // newline right after the delimiter is trimmed
+		p.advance()
+	}
+
+	var builder strings.Builder
+	for {
+		switch c := p.peek(); c {
+		case endOfInput:
+			return "", p.errorAt(startLine, startColumn, ErrStringNotClosed)
+		case '\'':
+			closed := p.parseMultiLineClosing(&builder, '\'')
+			if closed {
+				return builder.String(), nil
+			}
+		default:
+			builder.WriteByte(c)
+			p.advance()
+		}
+	}
+}
+
+// parseEscape parses the escape sequence starting with the backslash
+// at the current offset, and writes the decoded string to the builder.
+func (p *parser) parseEscape(builder *strings.Builder) (err error) {
+	escapeLine, escapeColumn := p.line, p.offset-p.lineStart
+	p.advance() // backslash
+	c := p.peek()
+	simpleEscapes := map[byte]byte{
+		'b': '\b', 't': '\t', 'n': '\n', 'f': '\f',
+		'r': '\r', '"': '"', '\\': '\\',
+	}
+	decoded, ok := simpleEscapes[c]
+	if ok {
+		builder.WriteByte(decoded)
+		p.advance()
+		return nil
+	}
+
+	var hexDigits int
+	switch c {
+	case 'u':
+		hexDigits = 4 //nolint:gomnd
+	case 'U':
+		hexDigits = 8 //nolint:gomnd
+	default:
+		return p.errorAt(escapeLine, escapeColumn,
+			fmt.Errorf("%w: \\%c", ErrEscapeSequence, c))
+	}
+
+	start := p.offset + 1
+	end := min(start+hexDigits, len(p.content))
+	sequence := p.content[p.offset:end]
+	const base, bitSize = 16, 32
+	codePoint, err := strconv.ParseUint(p.content[start:end], base, bitSize)
+	if err != nil || end-start != hexDigits || !utf8.ValidRune(rune(codePoint)) {
+		return p.errorAt(escapeLine, escapeColumn,
+			fmt.Errorf("%w: \\%s", ErrEscapeSequence, sequence))
+	}
+	builder.WriteRune(rune(codePoint))
+	p.offset = end
+	return nil
+}
+
+// parseScalar parses a boolean, a number or a date-time,
+// and returns its string representation.
+func (p *parser) parseScalar() (value string, err error) {
+	startColumn := p.offset - p.lineStart
+	start := p.offset
+	for isScalarCharacter(p.peek()) {
+		p.advance()
+	}
+
+	// A space can separate the date and the time of a date-time.
+	if localDateRegex.MatchString(p.content[start:p.offset]) &&
+		strings.HasPrefix(p.content[p.offset:], " ") &&
+		p.offset+2 < len(p.content) &&
+		isDigit(p.content[p.offset+1]) && isDigit(p.content[p.offset+2]) {
+		p.advance()
+		for isScalarCharacter(p.peek()) {
+			p.advance()
+		}
+	}
+
+	token := p.content[start:p.offset]
+	value, err = convertScalar(token)
+	if err != nil {
+		return "", p.errorAt(p.line, startColumn, err)
+	}
+	return value, nil
+}
+
+func isScalarCharacter(c byte) (ok bool) {
+	return isBareKeyCharacter(c) || c == '+' || c == '.' || c == ':'
+}
+
+func isDigit(c byte) (ok bool) {
+	return c >= '0' && c <= '9'
+}
+
+var (
+	localDateRegex    = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	dateTimeRegex     = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}|\d{2}:\d{2})`)
+	decimalRegex      = regexp.MustCompile(`^[+-]?(0|[1-9]\d*)$`)
+	floatRegex        = regexp.MustCompile(`^[+-]?(0|[1-9]\d*)(\.\d+)?([eE][+-]?\d+)?$`)
+	specialFloatRegex = regexp.MustCompile(`^[+-]?(inf|nan)$`)
+)
+
+func convertScalar(token string) (value string, err error) {
+	switch {
+	case token == "":
+		return "", ErrValueExpected
+	case token == "true", token == "false":
+		return token, nil
+	case dateTimeRegex.MatchString(token):
+		return convertDateTime(token)
+	case specialFloatRegex.MatchString(token):
+		return token, nil
+	case strings.HasPrefix(token, "0x"), strings.HasPrefix(token, "0o"),
+		strings.HasPrefix(token, "0b"):
+		return convertPrefixedInteger(token)
+	case !isDigit(token[0]) && token[0] != '+' && token[0] != '-':
+		return "", fmt.Errorf("%w: %s", ErrValueNotValid, token)
+	case strings.ContainsAny(token, ".eE"):
+		return convertFloat(token)
+	default:
+		return convertDecimalInteger(token)
+	}
+}
+
+// removeUnderscores removes underscores from the number given,
+// and returns ok as false if an underscore is not placed between
+// two digits.
+func removeUnderscores(number string, isDigit func(c byte) bool) (
+	cleaned string, ok bool) {
+	for i := 0; i < len(number); i++ {
+		if number[i] != '_' {
+			continue
+		}
+		if i == 0 || i == len(number)-1 ||
+			!isDigit(number[i-1]) || !isDigit(number[i+1]) {
+			return "", false
+		}
+	}
+	return strings.ReplaceAll(number, "_", ""), true
+}
+
+func convertDecimalInteger(token string) (value string, err error) {
+	cleaned, ok := removeUnderscores(token, isDigit)
+	if !ok || !decimalRegex.MatchString(cleaned) {
+		return "", fmt.Errorf("%w: %s", ErrInteger, token)
+	}
+	const base, bitSize = 10, 64
+	n, err := strconv.ParseInt(cleaned, base, bitSize)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInteger, err)
+	}
+	return strconv.FormatInt(n, base), nil
+}
+
+func convertPrefixedInteger(token string) (value string, err error) {
+	var base int
+	var isBaseDigit func(c byte) bool
+	switch token[1] {
+	case 'x':
+		base = 16
+		isBaseDigit = func(c byte) bool {
+			return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+		}
+	case 'o':
+		base = 8
+		isBaseDigit = func(c byte) bool { return c >= '0' && c <= '7' }
+	default:
+		base = 2
+		isBaseDigit = func(c byte) bool { return c == '0' || c == '1' }
+	}
+
+	const prefixLength = 2
+	cleaned, ok := removeUnderscores(token[prefixLength:], isBaseDigit)
+	if !ok || cleaned == "" {
+		return "", fmt.Errorf("%w: %s", ErrInteger, token)
+	}
+	for i := 0; i < len(cleaned); i++ {
+		if !isBaseDigit(cleaned[i]) {
+			return "", fmt.Errorf("%w: %s", ErrInteger, token)
+		}
+	}
+
+	const bitSize = 64
+	n, err := strconv.ParseInt(cleaned, base, bitSize)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInteger, err)
+	}
+	const outputBase = 10
+	return strconv.FormatInt(n, outputBase), nil
+}
+
+func convertFloat(token string) (value string, err error) {
+	cleaned, ok := removeUnderscores(token, isDigit)
+	if !ok || !floatRegex.MatchString(cleaned) {
+		return "", fmt.Errorf("%w: %s", ErrFloat, token)
+	}
+	const bitSize = 64
+	_, err = strconv.ParseFloat(cleaned, bitSize)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrFloat, err)
+	}
+	return cleaned, nil
+}
+
+// convertDateTime converts an offset date-time to a RFC 3339 string,
+// and a local date-time, local date or local time to a string in a
+// similar format without time offset.
+func convertDateTime(token string) (value string, err error) {
+	normalized := strings.ToUpper(token)
+	const dateLength = len("2006-01-02")
+	if len(normalized) > dateLength && normalized[dateLength] == ' ' {
+		normalized = normalized[:dateLength] + "T" + normalized[dateLength+1:]
+	}
+
+	// Note fractional seconds are accepted when parsing, even if
+	// the layout does not contain them.
+	conversions := []struct {
+		layout string
+		format string
+	}{
+		{layout: time.RFC3339, format: time.RFC3339Nano},
+		{layout: "2006-01-02T15:04:05", format: "2006-01-02T15:04:05.999999999"},
+		{layout: time.DateOnly, format: time.DateOnly},
+		{layout: time.TimeOnly, format: "15:04:05.999999999"},
+	}
+	for _, conversion := range conversions {
+		t, err := time.Parse(conversion.layout, normalized)
+		if err == nil {
+			return t.Format(conversion.format), nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrDateTime, token)
+}
@@ -0,0 +1,595 @@
+package yaml
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrMultipleDocuments  = errors.New("multiple documents are not supported")
+	ErrTabIndentation     = errors.New("tab character used for indentation")
+	ErrIndentation        = errors.New("unexpected indentation")
+	ErrMappingKeyExpected = errors.New("expected a mapping key")
+	ErrKeyDuplicate       = errors.New("duplicate mapping key")
+	ErrKeyNotScalar       = errors.New("mapping key is not a scalar")
+	ErrAnchorNameEmpty    = errors.New("anchor name is empty")
+	ErrAliasUndefined     = errors.New("alias is not defined")
+	ErrMergeValue         = errors.New("merge value is not a mapping or a sequence of mappings")
+	ErrBlockScalarHeader  = errors.New("block scalar header is not valid")
+	ErrTrailingCharacters = errors.New("unexpected characters")
+	ErrMappingValue       = errors.New("mapping value is not allowed in this context")
+)
+
+// parser is a line based YAML parser for block nodes,
+// which uses a scanner for flow nodes and quoted scalars.
+// Nodes are parsed as:
+//   - map[string]any for mappings
+//   - []any for sequences
+//   - string for non-null scalars
+//   - nil for null scalars.
+type parser struct {
+	lines   []string
+	index   int // index of the next line to parse
+	anchors map[string]any
+}
+
+func parse(content string) (root any, err error) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	// Remove the final line break so it does not produce an artificial
+	// empty last line, kept by block scalars with the `+` chomping.
+	content = strings.TrimSuffix(content, "\n")
+	lines, err := trimDocumentMarkers(strings.Split(content, "\n"))
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{
+		lines:   lines,
+		anchors: make(map[string]any),
+	}
+
+	const rootParentIndent = -1
+	root, err = p.parseBlock(rootParentIndent, false)
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipBlankLines()
+	if p.index < len(p.lines) {
+		indent, err := p.indentation(p.index)
+		if err != nil {
+			return nil, err
+		}
+		return nil, positionError(p.index, indent, ErrIndentation)
+	}
+
+	return root, nil
+}
+
+// trimDocumentMarkers blanks the `---` document start marker and
+// removes all lines from the `...` document end marker.
+// It returns an error if more than one document is found.
+func trimDocumentMarkers(lines []string) (trimmed []string, err error) {
+	documentStarted := false
+	for i, line := range lines {
+		switch {
+		case isDocumentMarker(line, "---"):
+			if documentStarted {
+				return nil, positionError(i, 0, ErrMultipleDocuments)
+			}
+			documentStarted = true
+			lines[i] = ""
+		case isDocumentMarker(line, "..."):
+			return lines[:i], nil
+		case !isBlankLine(line):
+			documentStarted = true
+		}
+	}
+	return lines, nil
+}
+
+func isDocumentMarker(line, marker string) (ok bool) {
+	line = strings.TrimRight(line, " \t")
+	return line == marker || strings.HasPrefix(line, marker+" #")
+}
+
+// isBlankLine returns true if the line is empty, contains only
+// spaces and tabs, or contains only a comment.
+func isBlankLine(line string) (blank bool) {
+	line = strings.TrimLeft(line, " \t")
+	return line == "" || line[0] == '#'
+}
+
+func (p *parser) skipBlankLines() {
+	for p.index < len(p.lines) && isBlankLine(p.lines[p.index]) {
+		p.index++
+	}
+}
+
+// indentation returns the number of leading spaces of the line at
+// the given line index, and an error if the indentation contains
+// a tab character.
+func (p *parser) indentation(lineIndex int) (indent int, err error) {
+	line := p.lines[lineIndex]
+	indent = countLeadingSpaces(line)
+	if indent < len(line) && line[indent] == '\t' {
+		return 0, positionError(lineIndex, indent, ErrTabIndentation)
+	}
+	return indent, nil
+}
+
+func countLeadingSpaces(line string) (count int) {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// parseBlock parses the block node starting at the next non blank line,
+// if this one is more indented than the parent indentation given.
+// If sequenceAtParent is true, a block sequence indented as much as its
+// parent is also accepted, which is the case for mapping values.
+// The node returned is nil if there is no such block node.
+func (p *parser) parseBlock(parentIndent int, sequenceAtParent bool) (node any, err error) {
+	p.skipBlankLines()
+	if p.index == len(p.lines) {
+		return nil, nil
+	}
+
+	indent, err := p.indentation(p.index)
+	if err != nil {
+		return nil, err
+	}
+	content := p.lines[p.index][indent:]
+	isSequence := isSequenceEntry(content)
+	if indent < parentIndent ||
+		(indent == parentIndent && (!sequenceAtParent || !isSequence)) {
+		return nil, nil
+	}
+
+	if isSequence {
+		return p.parseSequence(indent)
+	}
+
+	_, _, isMapping, err := p.mappingKey(p.index, indent)
+	if err != nil {
+		return nil, err
+	} else if isMapping {
+		return p.parseMapping(indent)
+	}
+
+	lineIndex := p.index
+	p.index++
+	return p.parseInlineValue(lineIndex, indent, parentIndent, sequenceAtParent)
+}
+
+func isSequenceEntry(content string) (ok bool) {
+	return content == "-" ||
+		strings.HasPrefix(content, "- ") ||
+		strings.HasPrefix(content, "-\t")
+}
+
+// parseSequence parses a block sequence where each entry starts
+// with a dash at the given indentation.
+func (p *parser) parseSequence(indent int) (sequence []any, err error) {
+	sequence = []any{}
+	for {
+		p.skipBlankLines()
+		if p.index == len(p.lines) {
+			return sequence, nil
+		}
+
+		lineIndent, err := p.indentation(p.index)
+		if err != nil {
+			return nil, err
+		}
+		line := p.lines[p.index]
+		switch {
+		case lineIndent < indent:
+			return sequence, nil
+		case lineIndent > indent:
+			return nil, positionError(p.index, lineIndent, ErrIndentation)
+		case !isSequenceEntry(line[indent:]):
+			return sequence, nil
+		}
+
+		// Replace the dash with a space so the entry content can be
+		// parsed as a block node more indented than the sequence.
+		p.lines[p.index] = line[:indent] + " " + line[indent+1:]
+		entry, err := p.parseBlock(indent, false)
+		if err != nil {
+			return nil, err
+		}
+		sequence = append(sequence, entry)
+	}
+}
+
+// parseMapping parses a block mapping where each key is at
+// the given indentation.
+func (p *parser) parseMapping(indent int) (mapping map[string]any, err error) {
+	mapping = make(map[string]any)
+	var merges []merge
+	for {
+		p.skipBlankLines()
+		if p.index == len(p.lines) {
+			break
+		}
+
+		lineIndent, err := p.indentation(p.index)
+		if err != nil {
+			return nil, err
+		} else if lineIndent < indent {
+			break
+		} else if lineIndent > indent {
+			return nil, positionError(p.index, lineIndent, ErrIndentation)
+		}
+
+		key, valueOffset, ok, err := p.mappingKey(p.index, indent)
+		if err != nil {
+			return nil, err
+		} else if !ok {
+			return nil, positionError(p.index, indent, ErrMappingKeyExpected)
+		}
+
+		lineIndex := p.index
+		p.index++
+		value, err := p.parseInlineValue(lineIndex, valueOffset, indent, true)
+		if err != nil {
+			return nil, err
+		}
+
+		if key == mergeKey {
+			merges = append(merges, merge{value: value, line: lineIndex, offset: indent})
+			continue
+		}
+
+		_, exists := mapping[key]
+		if exists {
+			return nil, positionError(lineIndex, indent,
+				fmt.Errorf("%w: %s", ErrKeyDuplicate, key))
+		}
+		mapping[key] = value
+	}
+
+	err = applyMerges(mapping, merges)
+	if err != nil {
+		return nil, err
+	}
+	return mapping, nil
+}
+
+// mappingKey returns the mapping key found at the given offset
+// of the line at the given line index, the offset of the value
+// after the colon, and ok set to true if a mapping key is found.
+func (p *parser) mappingKey(lineIndex, offset int) (key string,
+	valueOffset int, ok bool, err error) {
+	line := p.lines[lineIndex]
+	if offset == len(line) {
+		return "", 0, false, nil
+	}
+
+	switch line[offset] {
+	case '"', '\'':
+		s := &scanner{p: p, line: lineIndex, offset: offset}
+		key, err = s.parseQuoted()
+		if err != nil {
+			return "", 0, false, err
+		} else if s.line != lineIndex {
+			return "", 0, false, nil
+		}
+		rest := strings.TrimLeft(line[s.offset:], " \t")
+		if !strings.HasPrefix(rest, ":") ||
+			(len(rest) > 1 && rest[1] != ' ' && rest[1] != '\t') {
+			return "", 0, false, nil
+		}
+		valueOffset = len(line) - len(rest) + 1
+		return key, valueOffset, true, nil
+	case '[', '{', '&', '*', '!', '|', '>', '%', '@', '`', '#', '?':
+		return "", 0, false, nil
+	}
+
+	for i := offset; i < len(line); i++ {
+		switch line[i] {
+		case '#':
+			if line[i-1] == ' ' || line[i-1] == '\t' {
+				return "", 0, false, nil
+			}
+		case ':':
+			if i+1 == len(line) || line[i+1] == ' ' || line[i+1] == '\t' {
+				key = strings.TrimRight(line[offset:i], " \t")
+				return key, i + 1, true, nil
+			}
+		}
+	}
+	return "", 0, false, nil
+}
+
+// parseInlineValue parses the node starting at the given offset of the
+// line at the given line index, which can continue on the next lines.
+// The ownerIndent is the indentation of the mapping key or sequence
+// entry owning the node, and sequenceAtOwner indicates whether a block
+// sequence can be at the owner indentation.
+// The parser index must be set to the line after the given line index.
+func (p *parser) parseInlineValue(lineIndex, offset, ownerIndent int,
+	sequenceAtOwner bool) (node any, err error) {
+	line := p.lines[lineIndex]
+	offset = len(line) - len(strings.TrimLeft(line[offset:], " \t"))
+	if offset == len(line) || line[offset] == '#' {
+		return p.parseBlock(ownerIndent, sequenceAtOwner)
+	}
+
+	switch line[offset] {
+	case '&':
+		name, nextOffset := readName(line, offset+1, "")
+		if name == "" {
+			return nil, positionError(lineIndex, offset, ErrAnchorNameEmpty)
+		}
+		node, err = p.parseInlineValue(lineIndex, nextOffset, ownerIndent, sequenceAtOwner)
+		if err != nil {
+			return nil, err
+		}
+		p.anchors[name] = node
+		return node, nil
+	case '*':
+		name, nextOffset := readName(line, offset+1, "")
+		node, ok := p.anchors[name]
+		if !ok {
+			return nil, positionError(lineIndex, offset,
+				fmt.Errorf("%w: %s", ErrAliasUndefined, name))
+		}
+		return node, p.checkLineEnd(lineIndex, nextOffset)
+	case '|', '>':
+		return p.parseBlockScalar(lineIndex, offset, ownerIndent)
+	case '[', '{', '"', '\'':
+		s := &scanner{p: p, line: lineIndex, offset: offset}
+		node, err = s.parseNode()
+		if err != nil {
+			return nil, err
+		}
+		p.index = s.line + 1
+		return node, p.checkLineEnd(s.line, s.offset)
+	default:
+		return p.parsePlainScalar(lineIndex, offset, ownerIndent)
+	}
+}
+
+// readName reads an anchor or alias name starting at the given offset
+// of the line, until a space, a tab or one of the stop characters given.
+func readName(line string, offset int, stopCharacters string) (
+	name string, nextOffset int) {
+	end := offset
+	for end < len(line) && line[end] != ' ' && line[end] != '\t' &&
+		!strings.ContainsRune(stopCharacters, rune(line[end])) {
+		end++
+	}
+	return line[offset:end], end
+}
+
+// checkLineEnd returns an error if the line at the given line index
+// contains anything else than spaces and a comment from the given offset.
+func (p *parser) checkLineEnd(lineIndex, offset int) (err error) {
+	rest := p.lines[lineIndex][offset:]
+	trimmed := strings.TrimLeft(rest, " \t")
+	if trimmed == "" || (trimmed[0] == '#' && len(trimmed) < len(rest)) {
+		return nil
+	}
+	return positionError(lineIndex, offset+len(rest)-len(trimmed),
+		fmt.Errorf("%w: %s", ErrTrailingCharacters, trimmed))
+}
+
+// parsePlainScalar parses a plain scalar starting at the given offset
+// of the line at the given line index, and continuing on the next lines
+// more indented than the owner indentation.
+func (p *parser) parsePlainScalar(lineIndex, offset, ownerIndent int) (
+	node any, err error) {
+	text := stripComment(p.lines[lineIndex][offset:])
+	colonIndex := strings.Index(text+" ", ": ")
+	if colonIndex >= 0 {
+		return nil, positionError(lineIndex, offset+colonIndex, ErrMappingValue)
+	}
+
+	for p.index < len(p.lines) {
+		line := p.lines[p.index]
+		if isBlankLine(line) {
+			break
+		}
+		indent, err := p.indentation(p.index)
+		if err != nil {
+			return nil, err
+		} else if indent <= ownerIndent {
+			break
+		}
+
+		_, _, isMapping, err := p.mappingKey(p.index, indent)
+		if err != nil {
+			return nil, err
+		} else if isMapping {
+			return nil, positionError(p.index, indent, ErrIndentation)
+		}
+
+		text += " " + stripComment(line[indent:])
+		p.index++
+	}
+	return plainValue(text), nil
+}
+
+// stripComment removes an eventual comment from the string given,
+// and trims its trailing spaces.
+func stripComment(s string) (stripped string) {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '#' && (i == 0 || s[i-1] == ' ' || s[i-1] == '\t') {
+			s = s[:i]
+			break
+		}
+	}
+	return strings.TrimRight(s, " \t")
+}
+
+// plainValue returns nil if the plain scalar text given is a
+// null value, and the text otherwise.
+func plainValue(text string) (node any) {
+	switch text {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	default:
+		return text
+	}
+}
+
+// parseBlockScalar parses a literal or folded block scalar which
+// header starts at the given offset of the line at the given line index.
+func (p *parser) parseBlockScalar(lineIndex, offset, ownerIndent int) (
+	node any, err error) {
+	line := p.lines[lineIndex]
+	literal := line[offset] == '|'
+	var chomping byte
+	explicitIndent := 0
+	i := offset + 1
+headerLoop:
+	for ; i < len(line); i++ {
+		switch c := line[i]; {
+		case (c == '-' || c == '+') && chomping == 0:
+			chomping = c
+		case c >= '1' && c <= '9' && explicitIndent == 0:
+			explicitIndent = int(c - '0')
+		default:
+			break headerLoop
+		}
+	}
+	if i < len(line) && line[i] != ' ' && line[i] != '\t' {
+		return nil, positionError(lineIndex, i,
+			fmt.Errorf("%w: %s", ErrBlockScalarHeader, line[offset:]))
+	}
+	err = p.checkLineEnd(lineIndex, i)
+	if err != nil {
+		return nil, err
+	}
+
+	contentIndent := -1
+	if explicitIndent > 0 {
+		contentIndent = max(ownerIndent, 0) + explicitIndent
+	}
+
+	var contentLines []string
+	for ; p.index < len(p.lines); p.index++ {
+		line := p.lines[p.index]
+		if strings.TrimLeft(line, " ") == "" {
+			contentLines = append(contentLines, "")
+			continue
+		}
+		indent := countLeadingSpaces(line)
+		if contentIndent == -1 {
+			if indent <= ownerIndent {
+				break
+			}
+			contentIndent = indent
+		}
+		if indent < contentIndent {
+			break
+		}
+		contentLines = append(contentLines, line[contentIndent:])
+	}
+
+	trailingEmptyLines := 0
+	for len(contentLines) > 0 && contentLines[len(contentLines)-1] == "" {
+		trailingEmptyLines++
+		contentLines = contentLines[:len(contentLines)-1]
+	}
+
+	var text string
+	if literal {
+		text = strings.Join(contentLines, "\n")
+	} else {
+		text = fold(contentLines)
+	}
+
+	switch {
+	case chomping == '+':
+		text += strings.Repeat("\n", 1+trailingEmptyLines)
+	case chomping == '-', len(contentLines) == 0:
+	default:
+		text += "\n"
+	}
+	return text, nil
+}
+
+// fold folds the lines of a folded block scalar, where line
+// breaks between non empty lines are replaced by a space,
+// unless one of the lines is more indented.
+func fold(lines []string) (text string) {
+	var builder strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			previous := lines[i-1]
+			switch {
+			case line == "":
+				builder.WriteByte('\n')
+			case previous == "":
+				// line break already written for the empty line(s)
+			case isMoreIndented(line) || isMoreIndented(previous):
+				builder.WriteByte('\n')
+			default:
+				builder.WriteByte(' ')
+			}
+		}
+		builder.WriteString(line)
+	}
+	return builder.String()
+}
+
+func isMoreIndented(line string) (ok bool) {
+	return line != "" && (line[0] == ' ' || line[0] == '\t')
+}
+
+const mergeKey = "<<"
+
+type merge struct {
+	value  any
+	line   int
+	offset int
+}
+
+// applyMerges sets the keys of the merged mappings which are not
+// already set in the mapping given. Merges given first take
+// precedence over merges given last.
+func applyMerges(mapping map[string]any, merges []merge) (err error) {
+	for _, merge := range merges {
+		var sources []any
+		switch typedValue := merge.value.(type) {
+		case map[string]any:
+			sources = []any{typedValue}
+		case []any:
+			sources = typedValue
+		default:
+			return positionError(merge.line, merge.offset, ErrMergeValue)
+		}
+
+		for _, source := range sources {
+			sourceMapping, ok := source.(map[string]any)
+			if !ok {
+				return positionError(merge.line, merge.offset, ErrMergeValue)
+			}
+			for key, value := range sourceMapping {
+				_, exists := mapping[key]
+				if !exists {
+					mapping[key] = value
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// positionError returns an error wrapping the error given, with the
+// line number and column number in its message, computed from the
+// given 0-indexed line index and byte offset.
+func positionError(lineIndex, offset int, err error) error {
+	return fmt.Errorf("line %d column %d: %w", lineIndex+1, offset+1, err)
+}
+
+func describe(node any) (description string) {
+	switch node.(type) {
+	case []any:
+		return "sequence"
+	default:
+		return "scalar"
+	}
+}
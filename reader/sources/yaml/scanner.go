@@ -0,0 +1,340 @@
+package yaml
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrFlowNotClosed  = errors.New("flow collection is not closed")
+	ErrFlowSyntax     = errors.New("unexpected character in flow collection")
+	ErrQuoteNotClosed = errors.New("quote is not closed")
+	ErrEscapeSequence = errors.New("escape sequence is not valid")
+)
+
+// scanner scans flow nodes and quoted scalars, which can span
+// multiple lines, character by character.
+type scanner struct {
+	p      *parser
+	line   int
+	offset int
+}
+
+const endOfInput = 0
+
+// peek returns the current character, '\n' at the end of a line,
+// and endOfInput at the end of the input.
+func (s *scanner) peek() byte {
+	if s.line >= len(s.p.lines) {
+		return endOfInput
+	}
+	line := s.p.lines[s.line]
+	if s.offset >= len(line) {
+		return '\n'
+	}
+	return line[s.offset]
+}
+
+func (s *scanner) advance() {
+	if s.offset >= len(s.p.lines[s.line]) {
+		s.line++
+		s.offset = 0
+		return
+	}
+	s.offset++
+}
+
+// skipSpaces skips spaces, tabs, line breaks and comments.
+func (s *scanner) skipSpaces() {
+	for {
+		switch s.peek() {
+		case ' ', '\t', '\n':
+			s.advance()
+		case '#':
+			line := s.p.lines[s.line]
+			if s.offset > 0 && line[s.offset-1] != ' ' && line[s.offset-1] != '\t' {
+				return
+			}
+			s.offset = len(line)
+		default:
+			return
+		}
+	}
+}
+
+func (s *scanner) errorf(err error) error {
+	return positionError(s.line, s.offset, err)
+}
+
+// parseNode parses a flow node, which can be a flow sequence,
+// a flow mapping, a quoted scalar, an alias or a plain scalar,
+// optionally prefixed with an anchor.
+func (s *scanner) parseNode() (node any, err error) {
+	s.skipSpaces()
+	const stopCharacters = ",[]{}"
+	switch c := s.peek(); c {
+	case '[':
+		return s.parseSequence()
+	case '{':
+		return s.parseMapping()
+	case '"', '\'':
+		return s.parseQuoted()
+	case '&':
+		line := s.p.lines[s.line]
+		name, nextOffset := readName(line, s.offset+1, stopCharacters)
+		if name == "" {
+			return nil, s.errorf(ErrAnchorNameEmpty)
+		}
+		s.offset = nextOffset
+		node, err = s.parseNode()
+		if err != nil {
+			return nil, err
+		}
+		s.p.anchors[name] = node
+		return node, nil
+	case '*':
+		line := s.p.lines[s.line]
+		name, nextOffset := readName(line, s.offset+1, stopCharacters)
+		node, ok := s.p.anchors[name]
+		if !ok {
+			return nil, s.errorf(fmt.Errorf("%w: %s", ErrAliasUndefined, name))
+		}
+		s.offset = nextOffset
+		return node, nil
+	case ',', ']', '}', ':':
+		return nil, s.errorf(fmt.Errorf("%w: %q", ErrFlowSyntax, c))
+	default:
+		return s.parsePlain(), nil
+	}
+}
+
+func (s *scanner) parseSequence() (sequence []any, err error) {
+	openLine, openOffset := s.line, s.offset
+	s.advance() // [
+	sequence = []any{}
+	for {
+		s.skipSpaces()
+		switch s.peek() {
+		case endOfInput:
+			return nil, positionError(openLine, openOffset, ErrFlowNotClosed)
+		case ']':
+			s.advance()
+			return sequence, nil
+		}
+
+		node, err := s.parseNode()
+		if err != nil {
+			return nil, err
+		}
+		sequence = append(sequence, node)
+
+		s.skipSpaces()
+		switch c := s.peek(); c {
+		case ',':
+			s.advance()
+		case ']':
+			s.advance()
+			return sequence, nil
+		case endOfInput:
+			return nil, positionError(openLine, openOffset, ErrFlowNotClosed)
+		default:
+			return nil, s.errorf(fmt.Errorf("%w: %q instead of ',' or ']'",
+				ErrFlowSyntax, c))
+		}
+	}
+}
+
+func (s *scanner) parseMapping() (mapping map[string]any, err error) {
+	openLine, openOffset := s.line, s.offset
+	s.advance() // {
+	mapping = make(map[string]any)
+	var merges []merge
+	for {
+		s.skipSpaces()
+		switch s.peek() {
+		case endOfInput:
+			return nil, positionError(openLine, openOffset, ErrFlowNotClosed)
+		case '}':
+			s.advance()
+			return mapping, applyMerges(mapping, merges)
+		}
+
+		keyLine, keyOffset := s.line, s.offset
+		keyNode, err := s.parseNode()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := keyNode.(string)
+		if !ok && keyNode != nil {
+			return nil, positionError(keyLine, keyOffset, ErrKeyNotScalar)
+		}
+
+		var value any
+		s.skipSpaces()
+		if s.peek() == ':' {
+			s.advance()
+			s.skipSpaces()
+			if c := s.peek(); c != ',' && c != '}' && c != endOfInput {
+				value, err = s.parseNode()
+				if err != nil {
+					return nil, err
+				}
+				s.skipSpaces()
+			}
+		}
+
+		if key == mergeKey {
+			merges = append(merges, merge{value: value, line: keyLine, offset: keyOffset})
+		} else if _, exists := mapping[key]; exists {
+			return nil, positionError(keyLine, keyOffset,
+				fmt.Errorf("%w: %s", ErrKeyDuplicate, key))
+		} else {
+			mapping[key] = value
+		}
+
+		switch c := s.peek(); c {
+		case ',':
+			s.advance()
+		case '}':
+			s.advance()
+			return mapping, applyMerges(mapping, merges)
+		case endOfInput:
+			return nil, positionError(openLine, openOffset, ErrFlowNotClosed)
+		default:
+			return nil, s.errorf(fmt.Errorf("%w: %q instead of ',' or '}'",
+				ErrFlowSyntax, c))
+		}
+	}
+}
+
+// parsePlain parses a plain scalar in a flow collection,
+// which must fit on a single line.
+func (s *scanner) parsePlain() (node any) {
+	line := s.p.lines[s.line]
+	start := s.offset
+	end := start
+	for ; end < len(line); end++ {
+		c := line[end]
+		if strings.IndexByte(",[]{}", c) >= 0 ||
+			(c == ':' && (end+1 == len(line) ||
+				strings.IndexByte(" \t,[]{}", line[end+1]) >= 0)) ||
+			(c == '#' && end > start && (line[end-1] == ' ' || line[end-1] == '\t')) {
+			break
+		}
+	}
+	s.offset = end
+	return plainValue(strings.TrimRight(line[start:end], " \t"))
+}
+
+// parseQuoted parses a single or double quoted scalar, where line
+// breaks are folded into a space, or into line feeds for empty lines.
+func (s *scanner) parseQuoted() (value string, err error) {
+	quote := s.peek()
+	openLine, openOffset := s.line, s.offset
+	s.advance()
+	var buffer []byte
+	for {
+		line := s.p.lines[s.line]
+		if s.offset >= len(line) { // line break
+			buffer = []byte(strings.TrimRight(string(buffer), " \t"))
+			emptyLines, ok := s.nextContentLine()
+			if !ok {
+				return "", positionError(openLine, openOffset, ErrQuoteNotClosed)
+			}
+			if emptyLines == 0 {
+				buffer = append(buffer, ' ')
+			} else {
+				buffer = append(buffer, strings.Repeat("\n", emptyLines)...)
+			}
+			continue
+		}
+
+		c := line[s.offset]
+		switch {
+		case c == '\'' && quote == '\'':
+			if s.offset+1 < len(line) && line[s.offset+1] == '\'' {
+				buffer = append(buffer, '\'')
+				s.offset += 2
+				continue
+			}
+			s.offset++
+			return string(buffer), nil
+		case c == '"' && quote == '"':
+			s.offset++
+			return string(buffer), nil
+		case c == '\\' && quote == '"':
+			if s.offset+1 == len(line) { // escaped line break
+				_, ok := s.nextContentLine()
+				if !ok {
+					return "", positionError(openLine, openOffset, ErrQuoteNotClosed)
+				}
+				continue
+			}
+			decoded, size, err := unescape(line[s.offset+1:])
+			if err != nil {
+				return "", s.errorf(err)
+			}
+			buffer = append(buffer, decoded...)
+			s.offset += 1 + size
+		default:
+			buffer = append(buffer, c)
+			s.offset++
+		}
+	}
+}
+
+// nextContentLine moves the scanner to the first non-space character
+// of the next non empty line, and returns the number of empty lines
+// skipped. It returns ok as false if the end of the input is reached.
+func (s *scanner) nextContentLine() (emptyLines int, ok bool) {
+	for s.line++; s.line < len(s.p.lines); s.line++ {
+		line := s.p.lines[s.line]
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed != "" {
+			s.offset = len(line) - len(trimmed)
+			return emptyLines, true
+		}
+		emptyLines++
+	}
+	return 0, false
+}
+
+// unescape decodes the escape sequence at the start of the string
+// given, which is right after a backslash, and returns the decoded
+// string and the size of the escape sequence decoded.
+func unescape(s string) (decoded string, size int, err error) {
+	simpleEscapes := map[byte]string{
+		'0': "\x00", 'a': "\a", 'b': "\b", 't': "\t", '\t': "\t",
+		'n': "\n", 'v': "\v", 'f': "\f", 'r': "\r", 'e': "\x1b",
+		' ': " ", '"': "\"", '/': "/", '\\': "\\",
+		'N': "\u0085", '_': "\u00a0", 'L': "\u2028", 'P': "\u2029",
+	}
+	decoded, ok := simpleEscapes[s[0]]
+	if ok {
+		return decoded, 1, nil
+	}
+
+	var hexDigits int
+	switch s[0] {
+	case 'x':
+		hexDigits = 2 //nolint:gomnd
+	case 'u':
+		hexDigits = 4 //nolint:gomnd
+	case 'U':
+		hexDigits = 8 //nolint:gomnd
+	default:
+		return "", 0, fmt.Errorf("%w: \\%c", ErrEscapeSequence, s[0])
+	}
+
+	if len(s) < 1+hexDigits {
+		return "", 0, fmt.Errorf("%w: \\%s", ErrEscapeSequence, s)
+	}
+	const base, bitSize = 16, 32
+	codePoint, err := strconv.ParseUint(s[1:1+hexDigits], base, bitSize)
+	if err != nil {
+		return "", 0, fmt.Errorf("%w: \\%s", ErrEscapeSequence, s[:1+hexDigits])
+	}
+	return string(rune(codePoint)), 1 + hexDigits, nil
+}
@@ -0,0 +1,16 @@
+package yaml
+
+import (
+	"github.com/qdm12/gosettings"
+)
+
+// Settings contains settings for the YAML file source.
+type Settings struct {
+	// Path is the path to the YAML file to read.
+	// It defaults to "config.yaml".
+	Path string
+}
+
+func (s *Settings) setDefaults() {
+	s.Path = gosettings.DefaultComparable(s.Path, "config.yaml")
+}
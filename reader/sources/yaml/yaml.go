@@ -0,0 +1,114 @@
+package yaml
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/qdm12/gosettings/internal/flatten"
+	"golang.org/x/exp/maps"
+)
+
+// Source implements a YAML file settings source.
+// Nested mapping keys are flattened into keys joined with
+// underscores, such that `server: {listen_address: ":8000"}`
+// is accessible with the key SERVER_LISTEN_ADDRESS.
+// Note all keys are transformed using its KeyTransform method.
+type Source struct {
+	path       string
+	keyToValue map[string]string
+}
+
+// New creates a new YAML file source by reading and parsing
+// the YAML mapping in the file at the path given in settings.
+//
+// Only a subset of YAML is supported, without any dependency:
+//   - a single document, optionally starting with `---`
+//     and optionally ending with `...`
+//   - block mappings and block sequences
+//   - flow mappings and flow sequences, such as `{a: 1, b: [x, y]}`
+//   - plain, single quoted and double quoted scalars
+//   - literal `|` and folded `>` block scalars
+//   - comments
+//   - anchors, aliases and the `<<` merge key.
+//
+// Values are converted to strings such that:
+//   - scalars are kept as written in the file, without their quotes
+//   - null values (`~`, `null` or no value) are considered as unset
+//   - sequences of scalars are converted to comma separated values,
+//     to be used with the CSV methods, and cannot contain scalars
+//     with a comma
+//   - other sequences are flattened using the element index as key,
+//     for example SERVERS_0_ADDRESS.
+//
+// An error is returned if the file cannot be read, if its content
+// is malformed, with the line and column in the error message,
+// or if two different keys result in the same flattened key.
+func New(settings Settings) (source *Source, err error) {
+	settings.setDefaults()
+
+	content, err := os.ReadFile(settings.Path)
+	if err != nil {
+		return nil, fmt.Errorf("reading YAML file: %w", err)
+	}
+
+	keyToValue, err := parseAndFlatten(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("parsing YAML file %s: %w", settings.Path, err)
+	}
+
+	return &Source{
+		path:       settings.Path,
+		keyToValue: keyToValue,
+	}, nil
+}
+
+func (s *Source) String() string {
+	return "YAML file " + s.path
+}
+
+// Get returns the value of the flattened YAML key
+// given, and a boolean `isSet` to indicate if it is
+// set or not.
+func (s *Source) Get(key string) (value string, isSet bool) {
+	value, isSet = s.keyToValue[key]
+	return value, isSet
+}
+
+// KeyTransform transforms a generic key to a flattened
+// YAML key. It notably:
+// - Changes all characters to be uppercase
+// - Replaces all dashes, dots and spaces with underscores.
+func (s *Source) KeyTransform(key string) (newKey string) {
+	return flatten.NormalizeKey(key)
+}
+
+// Keys returns all the keys set in the YAML file.
+func (s *Source) Keys() (keys []string) {
+	return maps.Keys(s.keyToValue)
+}
+
+var (
+	ErrNotMapping  = errors.New("YAML document is not a mapping")
+	ErrKeyConflict = flatten.ErrKeyConflict
+	// ErrArrayElementComma is returned if a scalar of a sequence
+	// converted to comma separated values contains a comma.
+	ErrArrayElementComma = flatten.ErrArrayElementComma
+)
+
+func parseAndFlatten(content string) (keyToValue map[string]string, err error) {
+	root, err := parse(content)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping, ok := root.(map[string]any)
+	switch {
+	case root == nil:
+		return map[string]string{}, nil
+	case !ok:
+		return nil, fmt.Errorf("%w: %s", ErrNotMapping, describe(root))
+	}
+
+	return flatten.Flatten(mapping)
+}
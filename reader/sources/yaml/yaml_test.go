@@ -0,0 +1,369 @@
+package yaml
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		const content = "server:\n  listen_address: :8000\n"
+		err := os.WriteFile(path, []byte(content), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		source, err := New(Settings{Path: path})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expectedSource := &Source{
+			path: path,
+			keyToValue: map[string]string{
+				"SERVER_LISTEN_ADDRESS": ":8000",
+			},
+		}
+		if !reflect.DeepEqual(source, expectedSource) {
+			t.Errorf("expected source %#v, got %#v", expectedSource, source)
+		}
+
+		key := source.KeyTransform("server.listen-address")
+		value, isSet := source.Get(key)
+		if !isSet || value != ":8000" {
+			t.Errorf("expected value :8000 to be set, got %q (set %t)", value, isSet)
+		}
+	})
+
+	t.Run("parse_error", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		err := os.WriteFile(path, []byte("a: 1\n  b: 2\n"), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = New(Settings{Path: path})
+		errRegex := regexp.MustCompile(`^parsing YAML file /.+/config\.yaml: ` +
+			`line 2 column 3: unexpected indentation$`)
+		if err == nil || !errRegex.MatchString(err.Error()) {
+			t.Errorf("expected error to match %s, got %v", errRegex, err)
+		}
+	})
+
+	t.Run("file_not_found", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		_, err := New(Settings{Path: path})
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected error %v to wrap %v", err, os.ErrNotExist)
+		}
+	})
+}
+
+func Test_parseAndFlatten(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		content    string
+		keyToValue map[string]string
+		errWrapped error
+		errMessage string
+	}{
+		"empty": {
+			keyToValue: map[string]string{},
+		},
+		"comments_only": {
+			content:    "# comment\n\n  # other comment\n",
+			keyToValue: map[string]string{},
+		},
+		"document_markers": {
+			content: "--- # start\nkey: value\n...\nignored: [",
+			keyToValue: map[string]string{
+				"KEY": "value",
+			},
+		},
+		"block_mapping": {
+			content: `
+server:
+  listen-address: ":8000" # comment
+  tls:
+    enabled: yes
+    empty:
+    null_value: ~
+log level: info#not-comment
+url: http://host:80/path
+"quoted key": 'it''s'
+`,
+			keyToValue: map[string]string{
+				"SERVER_LISTEN_ADDRESS": ":8000",
+				"SERVER_TLS_ENABLED":    "yes",
+				"LOG_LEVEL":             "info#not-comment",
+				"URL":                   "http://host:80/path",
+				"QUOTED_KEY":            "it's",
+			},
+		},
+		"block_sequences": {
+			content: `
+names:
+- alice
+-   bob # comment
+- "carol"
+indented:
+  - 1
+  - 2
+servers:
+  - address: 1.2.3.4
+    port: 53
+  -
+    address: 5.6.7.8
+nested:
+- - a
+  - b
+- [c, d]
+`,
+			keyToValue: map[string]string{
+				"NAMES":             "alice,bob,carol",
+				"INDENTED":          "1,2",
+				"SERVERS_0_ADDRESS": "1.2.3.4",
+				"SERVERS_0_PORT":    "53",
+				"SERVERS_1_ADDRESS": "5.6.7.8",
+				"NESTED_0":          "a,b",
+				"NESTED_1":          "c,d",
+			},
+		},
+		"flow_collections": {
+			content: `
+sequence: [a, 'b', "c", ~]
+mapping: {a: 1, "b": [x, y], c: , d}
+empty_sequence: []
+empty_mapping: {}
+multi_line: [
+  first,  # comment
+  second,
+]
+url: [http://host:80]
+`,
+			keyToValue: map[string]string{
+				"SEQUENCE":       "a,b,c",
+				"MAPPING_A":      "1",
+				"MAPPING_B":      "x,y",
+				"EMPTY_SEQUENCE": "",
+				"MULTI_LINE":     "first,second",
+				"URL":            "http://host:80",
+			},
+		},
+		"scalars": {
+			content: `
+plain: multi word
+continued: first
+  second
+double: "tab\there\x41\u00e9 \"quoted\" \\"
+folded_double: "first
+  second
+
+  third \
+  fourth"
+single: 'a\n'
+`,
+			keyToValue: map[string]string{
+				"PLAIN":         "multi word",
+				"CONTINUED":     "first second",
+				"DOUBLE":        "tab\thereAé \"quoted\" \\",
+				"FOLDED_DOUBLE": "first second\nthird fourth",
+				"SINGLE":        `a\n`,
+			},
+		},
+		"block_scalars": {
+			content: `
+literal: |
+  line one
+    indented
+  line three
+
+folded: >
+  folded
+  text
+
+  new paragraph
+    more indented
+  end
+strip: |-
+  text
+
+keep: |+
+  text
+
+explicit: |2
+   leading space
+empty: |
+next: value
+`,
+			keyToValue: map[string]string{
+				"LITERAL":  "line one\n  indented\nline three\n",
+				"FOLDED":   "folded text\nnew paragraph\n  more indented\nend\n",
+				"STRIP":    "text",
+				"KEEP":     "text\n\n",
+				"EXPLICIT": " leading space\n",
+				"EMPTY":    "",
+				"NEXT":     "value",
+			},
+		},
+		"literal_keep_last_value": {
+			content:    "a: |+\n  x\n",
+			keyToValue: map[string]string{"A": "x\n"},
+		},
+		"folded_keep_last_value": {
+			content:    "a: >+\n  x\n\n",
+			keyToValue: map[string]string{"A": "x\n\n"},
+		},
+		"anchors_and_aliases": {
+			content: `
+base: &base
+  host: localhost
+  port: 80
+name: &name server
+other:
+  <<: *base
+  port: 8080
+  name: *name
+list:
+  - <<: [*base]
+    tls: true
+flow: {<<: *base, host: remote}
+`,
+			keyToValue: map[string]string{
+				"BASE_HOST":   "localhost",
+				"BASE_PORT":   "80",
+				"NAME":        "server",
+				"OTHER_HOST":  "localhost",
+				"OTHER_PORT":  "8080",
+				"OTHER_NAME":  "server",
+				"LIST_0_HOST": "localhost",
+				"LIST_0_PORT": "80",
+				"LIST_0_TLS":  "true",
+				"FLOW_HOST":   "remote",
+				"FLOW_PORT":   "80",
+			},
+		},
+		"not_mapping": {
+			content:    "- a\n- b",
+			errWrapped: ErrNotMapping,
+			errMessage: "YAML document is not a mapping: sequence",
+		},
+		"multiple_documents": {
+			content:    "a: 1\n---\nb: 2",
+			errWrapped: ErrMultipleDocuments,
+			errMessage: "line 2 column 1: multiple documents are not supported",
+		},
+		"tab_indentation": {
+			content:    "a:\n\tb: 1",
+			errWrapped: ErrTabIndentation,
+			errMessage: "line 2 column 1: tab character used for indentation",
+		},
+		"bad_indentation": {
+			content:    "a:\n    b: 1\n  c: 2",
+			errWrapped: ErrIndentation,
+			errMessage: "line 3 column 3: unexpected indentation",
+		},
+		"mapping_key_expected": {
+			content:    "a: 1\nb",
+			errWrapped: ErrMappingKeyExpected,
+			errMessage: "line 2 column 1: expected a mapping key",
+		},
+		"duplicate_key": {
+			content:    "a: 1\nb: 2\na: 3",
+			errWrapped: ErrKeyDuplicate,
+			errMessage: "line 3 column 1: duplicate mapping key: a",
+		},
+		"undefined_alias": {
+			content:    "a:\n  b: *missing",
+			errWrapped: ErrAliasUndefined,
+			errMessage: "line 2 column 6: alias is not defined: missing",
+		},
+		"invalid_merge": {
+			content:    "a: &a 1\nb:\n  <<: *a",
+			errWrapped: ErrMergeValue,
+			errMessage: "line 3 column 3: merge value is not a mapping or a sequence of mappings",
+		},
+		"flow_not_closed": {
+			content:    "a: 1\nb: [x,\n  y",
+			errWrapped: ErrFlowNotClosed,
+			errMessage: "line 2 column 4: flow collection is not closed",
+		},
+		"flow_syntax": {
+			content:    "a: {x: 1]}",
+			errWrapped: ErrFlowSyntax,
+			errMessage: `line 1 column 9: unexpected character in flow collection: ']' instead of ',' or '}'`,
+		},
+		"quote_not_closed": {
+			content:    "a: 'x\nb: 1",
+			errWrapped: ErrQuoteNotClosed,
+			errMessage: "line 1 column 4: quote is not closed",
+		},
+		"invalid_escape": {
+			content:    `a: "\q"`,
+			errWrapped: ErrEscapeSequence,
+			errMessage: `line 1 column 5: escape sequence is not valid: \q`,
+		},
+		"mapping_value_not_allowed": {
+			content:    "a: b: c",
+			errWrapped: ErrMappingValue,
+			errMessage: "line 1 column 5: mapping value is not allowed in this context",
+		},
+		"trailing_characters": {
+			content:    `a: "x" y`,
+			errWrapped: ErrTrailingCharacters,
+			errMessage: "line 1 column 8: unexpected characters: y",
+		},
+		"invalid_block_scalar_header": {
+			content:    "a: |x\n  text",
+			errWrapped: ErrBlockScalarHeader,
+			errMessage: "line 1 column 5: block scalar header is not valid: |x",
+		},
+		"sequence_scalar_with_comma": {
+			content:    "names:\n  - a\n  - \"b,c\"",
+			errWrapped: ErrArrayElementComma,
+			errMessage: `array element contains a comma: names[1] is "b,c"`,
+		},
+		"key_conflict": {
+			content:    "a:\n  b: 1\na_b: 2",
+			errWrapped: ErrKeyConflict,
+			errMessage: "flattened key conflict: a.b and a_b both map to A_B",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			keyToValue, err := parseAndFlatten(testCase.content)
+
+			if !errors.Is(err, testCase.errWrapped) {
+				t.Errorf("expected error %v to wrap %v", err, testCase.errWrapped)
+			}
+			if testCase.errWrapped != nil {
+				if err.Error() != testCase.errMessage {
+					t.Errorf("expected error message %q, got %q",
+						testCase.errMessage, err.Error())
+				}
+				return
+			}
+			if !reflect.DeepEqual(keyToValue, testCase.keyToValue) {
+				t.Errorf("expected %#v, got %#v", testCase.keyToValue, keyToValue)
+			}
+		})
+	}
+}
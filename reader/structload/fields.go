@@ -0,0 +1,258 @@
+package structload
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/qdm12/gosettings/reader"
+)
+
+// loadField loads the value at the given key into the field
+// given, and returns true if the key is set.
+func (l *loader) loadField(field reflect.Value, key string,
+	options []reader.Option) (set bool, err error) {
+	fieldType := field.Type()
+	switch fieldType.Kind() { //nolint:exhaustive
+	case reflect.Pointer:
+		newValue := reflect.New(fieldType.Elem())
+		set, err = l.loadValue(newValue.Elem(), key, options)
+		if set {
+			field.Set(newValue)
+		}
+		return set, err
+	case reflect.Slice:
+		return l.loadSlice(field, key, options)
+	default:
+		return l.loadValue(field, key, options)
+	}
+}
+
+// loadValue loads the value at the given key into the
+// addressable value given, and returns true if the key is set.
+func (l *loader) loadValue(value reflect.Value, key string,
+	options []reader.Option) (set bool, err error) {
+	r := l.reader
+	valueType := value.Type()
+	switch valueType {
+	case durationType:
+		pointer, err := r.DurationPtr(key, options...)
+		return setFromPointer(value, pointer, err)
+	case timeType:
+		pointer, err := r.TimePtr(key, options...)
+		return setFromPointer(value, pointer, err)
+	case addrType:
+		netipValue, err := r.NetipAddr(key, options...)
+		return setIfValid(value, netipValue, err)
+	case addrPortType:
+		netipValue, err := r.NetipAddrPort(key, options...)
+		return setIfValid(value, netipValue, err)
+	case prefixType:
+		netipValue, err := r.NetipPrefix(key, options...)
+		return setIfValid(value, netipValue, err)
+	}
+
+	if reflect.PointerTo(valueType).Implements(textUnmarshalerType) {
+		lookup, err := r.Lookup(key, options...)
+		if err != nil {
+			return false, err
+		} else if !lookup.IsSet {
+			return false, nil
+		}
+		unmarshaler := value.Addr().Interface().(encoding.TextUnmarshaler) //nolint:forcetypeassert
+		err = unmarshaler.UnmarshalText([]byte(lookup.Value))
+		if err != nil {
+			return false, fmt.Errorf("%s %s: %w", lookup.Source, key, err)
+		}
+		return true, nil
+	}
+
+	switch valueType.Kind() { //nolint:exhaustive
+	case reflect.String:
+		pointer, err := r.GetWithError(key, options...)
+		return setFromPointer(value, pointer, err)
+	case reflect.Bool:
+		pointer, err := r.BoolPtr(key, options...)
+		return setFromPointer(value, pointer, err)
+	case reflect.Int:
+		pointer, err := r.IntPtr(key, options...)
+		return setFromPointer(value, pointer, err)
+	case reflect.Int8:
+		pointer, err := r.Int8Ptr(key, options...)
+		return setFromPointer(value, pointer, err)
+	case reflect.Int16:
+		pointer, err := r.Int16Ptr(key, options...)
+		return setFromPointer(value, pointer, err)
+	case reflect.Int32:
+		pointer, err := r.Int32Ptr(key, options...)
+		return setFromPointer(value, pointer, err)
+	case reflect.Int64:
+		pointer, err := r.Int64Ptr(key, options...)
+		return setFromPointer(value, pointer, err)
+	case reflect.Uint:
+		pointer, err := r.UintPtr(key, options...)
+		return setFromPointer(value, pointer, err)
+	case reflect.Uint8:
+		pointer, err := r.Uint8Ptr(key, options...)
+		return setFromPointer(value, pointer, err)
+	case reflect.Uint16:
+		pointer, err := r.Uint16Ptr(key, options...)
+		return setFromPointer(value, pointer, err)
+	case reflect.Uint32:
+		pointer, err := r.Uint32Ptr(key, options...)
+		return setFromPointer(value, pointer, err)
+	case reflect.Uint64:
+		pointer, err := r.Uint64Ptr(key, options...)
+		return setFromPointer(value, pointer, err)
+	case reflect.Float32:
+		pointer, err := r.Float32Ptr(key, options...)
+		return setFromPointer(value, pointer, err)
+	case reflect.Float64:
+		pointer, err := r.Float64Ptr(key, options...)
+		return setFromPointer(value, pointer, err)
+	default:
+		return false, fmt.Errorf("%w: %s for key %s", ErrFieldTypeNotSupported, valueType, key)
+	}
+}
+
+// setFromPointer sets the value given to the value pointed to by
+// the pointer argument, converted to the value type, if the pointer
+// is not nil and the error is nil.
+func setFromPointer[T any](value reflect.Value, pointer *T, err error) (
+	set bool, errOut error) {
+	if err != nil || pointer == nil {
+		return false, err
+	}
+	value.Set(reflect.ValueOf(*pointer).Convert(value.Type()))
+	return true, nil
+}
+
+// setIfValid sets the value given to the netip value argument,
+// if it is valid and the error is nil.
+func setIfValid[T interface{ IsValid() bool }](value reflect.Value,
+	netipValue T, err error) (set bool, errOut error) {
+	if err != nil || !netipValue.IsValid() {
+		return false, err
+	}
+	value.Set(reflect.ValueOf(netipValue))
+	return true, nil
+}
+
+// loadSlice loads the comma separated value at the given key into
+// the slice field given, and returns true if the key is set.
+func (l *loader) loadSlice(field reflect.Value, key string,
+	options []reader.Option) (set bool, err error) {
+	r := l.reader
+	elementType := field.Type().Elem()
+	var values any
+	switch elementType {
+	case addrType:
+		values, err = r.CSVNetipAddresses(key, options...)
+	case addrPortType:
+		values, err = r.CSVNetipAddrPorts(key, options...)
+	case prefixType:
+		values, err = r.CSVNetipPrefixes(key, options...)
+	case durationType:
+		values, err = l.csvParse(key, options, func(s string) (any, error) {
+			return time.ParseDuration(s)
+		})
+	default:
+		values, err = l.csvByKind(field, key, options)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	anyValues := toAnySlice(values)
+	if anyValues == nil {
+		return false, nil
+	}
+
+	newSlice := reflect.MakeSlice(field.Type(), len(anyValues), len(anyValues))
+	for i, anyValue := range anyValues {
+		newSlice.Index(i).Set(reflect.ValueOf(anyValue).Convert(elementType))
+	}
+	field.Set(newSlice)
+	return true, nil
+}
+
+// toAnySlice converts the slice given to a slice of any,
+// and returns nil if the slice given is nil.
+func toAnySlice(slice any) (anySlice []any) {
+	sliceValue := reflect.ValueOf(slice)
+	if !sliceValue.IsValid() || sliceValue.IsNil() {
+		return nil
+	}
+	anySlice = make([]any, sliceValue.Len())
+	for i := range anySlice {
+		anySlice[i] = sliceValue.Index(i).Interface()
+	}
+	return anySlice
+}
+
+// csvParse reads the comma separated value at the given key
+// and parses each of its elements using the parse function given.
+func (l *loader) csvParse(key string, options []reader.Option,
+	parse func(s string) (any, error)) (values []any, err error) {
+	stringValues, err := l.reader.CSVWithError(key, options...)
+	if err != nil {
+		return nil, err
+	} else if stringValues == nil {
+		return nil, nil
+	}
+
+	values = make([]any, len(stringValues))
+	for i, stringValue := range stringValues {
+		values[i], err = parse(stringValue)
+		if err != nil {
+			// Note the lookup error is the same as the CSV one, which is nil.
+			lookup, _ := l.reader.Lookup(key, options...)
+			return nil, fmt.Errorf("%s %s: %w", lookup.Source, key, err)
+		}
+	}
+	return values, nil
+}
+
+func (l *loader) csvByKind(field reflect.Value, key string,
+	options []reader.Option) (values any, err error) {
+	r := l.reader
+	elementType := field.Type().Elem()
+
+	if reflect.PointerTo(elementType).Implements(textUnmarshalerType) {
+		return l.csvParse(key, options, func(s string) (any, error) {
+			element := reflect.New(elementType)
+			unmarshaler := element.Interface().(encoding.TextUnmarshaler) //nolint:forcetypeassert
+			err := unmarshaler.UnmarshalText([]byte(s))
+			return element.Elem().Interface(), err
+		})
+	}
+
+	switch elementType.Kind() { //nolint:exhaustive
+	case reflect.String:
+		values, err = r.CSVWithError(key, options...)
+	case reflect.Int:
+		values, err = r.CSVInt(key, options...)
+	case reflect.Int8:
+		values, err = r.CSVInt8(key, options...)
+	case reflect.Int16:
+		values, err = r.CSVInt16(key, options...)
+	case reflect.Int32:
+		values, err = r.CSVInt32(key, options...)
+	case reflect.Int64:
+		values, err = r.CSVInt64(key, options...)
+	case reflect.Uint:
+		values, err = r.CSVUint(key, options...)
+	case reflect.Uint8:
+		values, err = r.CSVUint8(key, options...)
+	case reflect.Uint16:
+		values, err = r.CSVUint16(key, options...)
+	case reflect.Uint32:
+		values, err = r.CSVUint32(key, options...)
+	case reflect.Uint64:
+		values, err = r.CSVUint64(key, options...)
+	default:
+		return nil, fmt.Errorf("%w: %s for key %s", ErrFieldTypeNotSupported, field.Type(), key)
+	}
+	return values, err
+}
@@ -0,0 +1,208 @@
+// Package structload fills a settings struct from a reader.Reader
+// using struct field tags. It uses reflection, and is therefore
+// kept separate from the reflection-free reader package.
+//
+// The following struct field tags are supported:
+//   - `key:"LISTEN_ADDRESS"` is the key to read for the field. For a
+//     nested struct field, it is the prefix added to the keys of its
+//     fields, separated by an underscore. Fields without a key tag
+//     are ignored, except nested structs which are then read without
+//     prefix. The key `-` makes the field always ignored.
+//   - `retro:"OLD_ADDRESS,OLDER_ADDRESS"` is a comma separated list
+//     of retro-compatible keys, see reader.RetroKeys.
+//   - `accept_empty:"true"` accepts empty values, see reader.AcceptEmpty.
+//   - `lowercase:"false"` disables lowercasing the value, see
+//     reader.ForceLowercase.
+//   - `expand:"true"` expands references in the value, see reader.Expand.
+//   - `secret:"true"` obfuscates the value in the reader record,
+//     see reader.Secret.
+package structload
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/netip"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qdm12/gosettings/reader"
+)
+
+var (
+	ErrTargetNotStructPointer = errors.New("target is not a non-nil pointer to a struct")
+	ErrTagValueNotValid       = errors.New("tag value is not valid")
+	ErrFieldTypeNotSupported  = errors.New("field type is not supported")
+)
+
+// Load fills the struct pointed to by target with values read
+// from the reader given, using the struct field tags described
+// in the package documentation.
+// Fields are only modified if their key is set, so defaults
+// can be set before or after calling Load.
+// The field types supported are:
+//   - string, bool, integers and floats, including named types
+//   - time.Duration, time.Time, netip.Addr, netip.AddrPort and
+//     netip.Prefix
+//   - types implementing encoding.TextUnmarshaler
+//   - pointers to the types above, set to a new value if the key is set
+//   - slices of strings, integers, time.Duration, netip types and
+//     encoding.TextUnmarshaler types, read as comma separated values
+//   - nested structs and pointers to nested structs, where a nil
+//     pointer is only set if one of the nested fields is set.
+//
+// All the errors encountered are returned joined together.
+func Load(r *reader.Reader, target any) (err error) {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Pointer || value.IsNil() ||
+		value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %T", ErrTargetNotStructPointer, target)
+	}
+
+	l := &loader{reader: r}
+	l.loadStruct(value.Elem(), "")
+	return errors.Join(l.errs...)
+}
+
+type loader struct {
+	reader *reader.Reader
+	errs   []error
+}
+
+// Reflect types compared against field types, which are
+// read-only once initialized.
+var ( //nolint:gochecknoglobals
+	durationType        = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
+	addrType            = reflect.TypeOf(netip.Addr{})
+	addrPortType        = reflect.TypeOf(netip.AddrPort{})
+	prefixType          = reflect.TypeOf(netip.Prefix{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// loadStruct loads the fields of the struct value given,
+// and returns true if at least one field was set.
+func (l *loader) loadStruct(structValue reflect.Value, prefix string) (set bool) {
+	structType := structValue.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		structField := structType.Field(i)
+		// Note exported fields of an unexported embedded struct
+		// can still be set.
+		embeddedStruct := structField.Anonymous &&
+			structField.Type.Kind() == reflect.Struct
+		if !structField.IsExported() && !embeddedStruct {
+			continue
+		}
+
+		key := structField.Tag.Get("key")
+		if key == "-" {
+			continue
+		}
+
+		field := structValue.Field(i)
+		if isNestedStruct(structField.Type) {
+			nestedPrefix := prefix
+			if key != "" {
+				nestedPrefix += key + "_"
+			}
+			set = l.loadNestedStruct(field, nestedPrefix) || set
+			continue
+		} else if key == "" {
+			continue
+		}
+
+		options, err := parseOptions(structField, prefix)
+		if err != nil {
+			l.errs = append(l.errs, err)
+			continue
+		}
+
+		fieldSet, err := l.loadField(field, prefix+key, options)
+		if err != nil {
+			l.errs = append(l.errs, err)
+			continue
+		}
+		set = fieldSet || set
+	}
+	return set
+}
+
+func isNestedStruct(fieldType reflect.Type) (ok bool) {
+	if fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+	return fieldType.Kind() == reflect.Struct &&
+		!isSpecialType(fieldType) &&
+		!reflect.PointerTo(fieldType).Implements(textUnmarshalerType)
+}
+
+func isSpecialType(t reflect.Type) (ok bool) {
+	switch t {
+	case durationType, timeType, addrType, addrPortType, prefixType:
+		return true
+	default:
+		return false
+	}
+}
+
+func (l *loader) loadNestedStruct(field reflect.Value, prefix string) (set bool) {
+	if field.Kind() != reflect.Pointer {
+		return l.loadStruct(field, prefix)
+	}
+
+	if !field.IsNil() {
+		return l.loadStruct(field.Elem(), prefix)
+	}
+	newStruct := reflect.New(field.Type().Elem())
+	set = l.loadStruct(newStruct.Elem(), prefix)
+	if set {
+		field.Set(newStruct)
+	}
+	return set
+}
+
+func parseOptions(structField reflect.StructField, prefix string) (
+	options []reader.Option, err error) {
+	retroKeys := structField.Tag.Get("retro")
+	if retroKeys != "" {
+		keys := strings.Split(retroKeys, ",")
+		for i := range keys {
+			keys[i] = prefix + strings.TrimSpace(keys[i])
+		}
+		options = append(options, reader.RetroKeys(keys...))
+	}
+
+	boolTags := []struct {
+		name      string
+		newOption func(value bool) reader.Option
+	}{
+		{name: "accept_empty", newOption: reader.AcceptEmpty},
+		{name: "lowercase", newOption: reader.ForceLowercase},
+		{name: "expand", newOption: reader.Expand},
+		{name: "secret", newOption: func(secret bool) reader.Option {
+			if !secret {
+				return nil
+			}
+			return reader.Secret()
+		}},
+	}
+	for _, boolTag := range boolTags {
+		tagValue, ok := structField.Tag.Lookup(boolTag.name)
+		if !ok {
+			continue
+		}
+		value, err := strconv.ParseBool(tagValue)
+		if err != nil {
+			return nil, fmt.Errorf("%w: field %s tag %s: %s",
+				ErrTagValueNotValid, structField.Name, boolTag.name, tagValue)
+		}
+		option := boolTag.newOption(value)
+		if option != nil {
+			options = append(options, option)
+		}
+	}
+
+	return options, nil
+}
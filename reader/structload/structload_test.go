@@ -0,0 +1,185 @@
+package structload
+
+import (
+	"errors"
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gosettings/reader"
+	"github.com/qdm12/gosettings/reader/sources/env"
+)
+
+type level int
+
+func (l *level) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "debug":
+		*l = 0
+	case "info":
+		*l = 1
+	default:
+		return errors.New("unknown level")
+	}
+	return nil
+}
+
+type port uint16
+
+type testSettings struct {
+	Name      string          `key:"NAME" lowercase:"false"`
+	Empty     *string         `key:"EMPTY" accept_empty:"true"`
+	Enabled   *bool           `key:"ENABLED"`
+	Port      port            `key:"PORT" retro:"OLD_PORT"`
+	Ratio     float64         `key:"RATIO"`
+	Timeout   time.Duration   `key:"TIMEOUT"`
+	Start     *time.Time      `key:"START"`
+	Address   netip.Addr      `key:"ADDRESS"`
+	Level     level           `key:"LEVEL"`
+	Names     []string        `key:"NAMES"`
+	Ports     []port          `key:"PORTS"`
+	Prefixes  []netip.Prefix  `key:"PREFIXES"`
+	Durations []time.Duration `key:"DURATIONS"`
+	Levels    []level         `key:"LEVELS"`
+	Unset     *int            `key:"UNSET"`
+	Untagged  string
+	Ignored   string         `key:"-"`
+	Server    serverSettings `key:"SERVER"`
+	Log       *logSettings   `key:"LOG"`
+	Unused    *logSettings   `key:"UNUSED"`
+	serverSettings
+	unexported string `key:"NAME"` //nolint:unused
+}
+
+type serverSettings struct {
+	Address string `key:"ADDRESS"`
+	Secret  string `key:"SECRET" secret:"true"`
+}
+
+type logSettings struct {
+	Level string `key:"LEVEL"`
+}
+
+func Test_Load(t *testing.T) {
+	t.Parallel()
+
+	source := env.New(env.Settings{Environ: []string{
+		"NAME=MyName",
+		"EMPTY=",
+		"ENABLED=yes",
+		"OLD_PORT=8000",
+		"RATIO=0.5",
+		"TIMEOUT=1s",
+		"START=2024-01-02T03:04:05Z",
+		"ADDRESS=1.2.3.4",
+		"LEVEL=info",
+		"NAMES=a,b",
+		"PORTS=1,2",
+		"PREFIXES=1.2.3.0/24",
+		"DURATIONS=1s,2m",
+		"LEVELS=debug,info",
+		"UNTAGGED=x",
+		"IGNORED=x",
+		"SERVER_ADDRESS=:8000",
+		"SERVER_SECRET=secret",
+		"LOG_LEVEL=debug",
+		"SECRET=top",
+	}})
+	r := reader.New(reader.Settings{Sources: []reader.Source{source}})
+
+	var settings testSettings
+	err := Load(r, &settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := testSettings{
+		Name:      "MyName",
+		Empty:     ptrTo(""),
+		Enabled:   ptrTo(true),
+		Port:      8000,
+		Ratio:     0.5,
+		Timeout:   time.Second,
+		Start:     ptrTo(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+		Address:   netip.AddrFrom4([4]byte{1, 2, 3, 4}),
+		Level:     1,
+		Names:     []string{"a", "b"},
+		Ports:     []port{1, 2},
+		Prefixes:  []netip.Prefix{netip.MustParsePrefix("1.2.3.0/24")},
+		Durations: []time.Duration{time.Second, 2 * time.Minute},
+		Levels:    []level{0, 1},
+		Server: serverSettings{
+			Address: ":8000",
+			Secret:  "secret",
+		},
+		Log: &logSettings{Level: "debug"},
+		serverSettings: serverSettings{
+			Address: "1.2.3.4",
+			Secret:  "top",
+		},
+	}
+	if !reflect.DeepEqual(settings, expected) {
+		t.Errorf("expected %+v, got %+v", expected, settings)
+	}
+}
+
+func Test_Load_errors(t *testing.T) {
+	t.Parallel()
+
+	source := env.New(env.Settings{Environ: []string{
+		"PORT=70000",
+		"LEVEL=unknown",
+		"LEVELS=debug,unknown",
+	}})
+	r := reader.New(reader.Settings{Sources: []reader.Source{source}})
+
+	var settings struct {
+		Port     port              `key:"PORT"`
+		Level    level             `key:"LEVEL"`
+		Levels   []level           `key:"LEVELS"`
+		Bad      bool              `key:"BAD" accept_empty:"maybe"`
+		Channels []chan struct{}   `key:"CHANNELS"`
+		Map      map[string]string `key:"MAP"`
+	}
+	err := Load(r, &settings)
+
+	if !errors.Is(err, reader.ErrValueNotInRange) {
+		t.Errorf("expected error %v to wrap %v", err, reader.ErrValueNotInRange)
+	}
+	if !errors.Is(err, ErrTagValueNotValid) {
+		t.Errorf("expected error %v to wrap %v", err, ErrTagValueNotValid)
+	}
+	if !errors.Is(err, ErrFieldTypeNotSupported) {
+		t.Errorf("expected error %v to wrap %v", err, ErrFieldTypeNotSupported)
+	}
+	expectedLines := []string{
+		"environment variable PORT: value is not in range: 70000 is not between 0 and 65535",
+		"environment variable LEVEL: unknown level",
+		"environment variable LEVELS: unknown level",
+		"tag value is not valid: field Bad tag accept_empty: maybe",
+		"field type is not supported: []chan struct {} for key CHANNELS",
+		"field type is not supported: map[string]string for key MAP",
+	}
+	if err == nil || err.Error() != strings.Join(expectedLines, "\n") {
+		t.Errorf("expected error:\n%s\ngot:\n%v", strings.Join(expectedLines, "\n"), err)
+	}
+}
+
+func Test_Load_target(t *testing.T) {
+	t.Parallel()
+
+	r := reader.New(reader.Settings{Sources: []reader.Source{&env.Source{}}})
+
+	targets := []any{nil, testSettings{}, (*testSettings)(nil), ptrTo(1)}
+	for _, target := range targets {
+		err := Load(r, target)
+		if !errors.Is(err, ErrTargetNotStructPointer) {
+			t.Errorf("for target %#v: expected error %v to wrap %v",
+				target, err, ErrTargetNotStructPointer)
+		}
+	}
+}
+
+func ptrTo[T any](x T) *T { return &x }
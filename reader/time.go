@@ -33,3 +33,34 @@ func (r *Reader) Duration(key string, options ...Option) (
 	parseOptions := r.makeParseOptions(options)
 	return parse.Duration(r.sources, key, parseOptions...)
 }
+
+// TimePtr returns a pointer to a `time.Time` from the value
+// found at the given key.
+// The value can be a RFC 3339 date-time, or a date-time or date
+// without time offset, which is then interpreted as UTC.
+// If the value is not a valid time string, an error is returned
+// with the source and key in its message.
+// The value is returned as `nil` if:
+//   - the given key is NOT set.
+//   - By default and unless changed by the AllowEmpty option, if the
+//     given key is set and its corresponding value is empty.
+func (r *Reader) TimePtr(key string, options ...Option) (
+	timePtr *time.Time, err error) {
+	parseOptions := r.makeParseOptions(options)
+	return parse.TimePtr(r.sources, key, parseOptions...)
+}
+
+// Time returns a `time.Time` from the value found at the given key.
+// The value can be a RFC 3339 date-time, or a date-time or date
+// without time offset, which is then interpreted as UTC.
+// If the value is not a valid time string, an error is returned
+// with the source and key in its message.
+// The value is returned as the zero `time.Time{}` if:
+//   - the given key is NOT set.
+//   - By default and unless changed by the AllowEmpty option, if the
+//     given key is set and its corresponding value is empty.
+func (r *Reader) Time(key string, options ...Option) (
+	t time.Time, err error) {
+	parseOptions := r.makeParseOptions(options)
+	return parse.Time(r.sources, key, parseOptions...)
+}
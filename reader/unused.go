@@ -0,0 +1,183 @@
+package reader
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type queriedKeys struct {
+	mutex sync.Mutex
+	keys  map[string]struct{}
+}
+
+func newQueriedKeys() *queriedKeys {
+	return &queriedKeys{
+		keys: make(map[string]struct{}),
+	}
+}
+
+func (q *queriedKeys) add(keys ...string) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	for _, key := range keys {
+		q.keys[key] = struct{}{}
+	}
+}
+
+func (q *queriedKeys) list() (keys []string) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	keys = make([]string, 0, len(q.keys))
+	for key := range q.keys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// UnusedKey is a key set in a source but never queried on the reader.
+type UnusedKey struct {
+	// Source is the name of the source containing the key,
+	// for example 'environment variable'.
+	Source string
+	// Key is the key in the form given by the source KeyTransform
+	// method, for example SERVER_ADRESS.
+	Key string
+	// Suggestion is the closest queried key, in the form given by
+	// the source KeyTransform method, for example SERVER_ADDRESS.
+	// It is empty if no queried key is close enough.
+	Suggestion string
+}
+
+func (u UnusedKey) String() string {
+	s := u.Source + " " + u.Key
+	if u.Suggestion != "" {
+		s += " (did you mean " + u.Suggestion + "?)"
+	}
+	return s
+}
+
+// UnusedKeys returns the keys set in the reader sources which
+// were never queried on the reader, with a suggestion of the
+// closest queried key for each of them to detect misspelled keys.
+// It should be called once all the settings are read.
+// Only sources implementing the KeyLister interface are checked,
+// and the environment variable source only lists keys starting
+// with its KeyPrefix settings field, and no key if it is empty.
+// Keys are returned grouped by source in the order of the sources,
+// and sorted alphabetically for each source.
+func (r *Reader) UnusedKeys() (unusedKeys []UnusedKey) {
+	return r.unusedKeys(false)
+}
+
+var ErrUnusedKeys = errors.New("unused keys found")
+
+// CheckUnusedKeys returns an error listing the keys never queried
+// in sources implementing the Strict interface and returning true,
+// such as a flag source created with flag.NewStrict.
+// It returns nil if there is no such unused key.
+// It should be called once all the settings are read.
+func (r *Reader) CheckUnusedKeys() (err error) {
+	unusedKeys := r.unusedKeys(true)
+	if len(unusedKeys) == 0 {
+		return nil
+	}
+
+	unusedKeyStrings := make([]string, len(unusedKeys))
+	for i, unusedKey := range unusedKeys {
+		unusedKeyStrings[i] = unusedKey.String()
+	}
+	return fmt.Errorf("%w: %s", ErrUnusedKeys, strings.Join(unusedKeyStrings, ", "))
+}
+
+func (r *Reader) unusedKeys(strictOnly bool) (unusedKeys []UnusedKey) {
+	queriedKeys := r.queried.list()
+
+	for _, source := range r.sources {
+		keyLister, ok := source.(KeyLister)
+		if !ok {
+			continue
+		}
+		if strictOnly {
+			strict, ok := source.(Strict)
+			if !ok || !strict.Strict() {
+				continue
+			}
+		}
+
+		usedKeys := make(map[string]struct{}, len(queriedKeys))
+		usedKeysSlice := make([]string, 0, len(queriedKeys))
+		for _, queriedKey := range queriedKeys {
+			usedKey := source.KeyTransform(queriedKey)
+			_, exists := usedKeys[usedKey]
+			if exists {
+				continue
+			}
+			usedKeys[usedKey] = struct{}{}
+			usedKeysSlice = append(usedKeysSlice, usedKey)
+		}
+
+		sourceKeys := keyLister.Keys()
+		sort.Strings(sourceKeys)
+		for _, sourceKey := range sourceKeys {
+			_, used := usedKeys[sourceKey]
+			if used {
+				continue
+			}
+			unusedKeys = append(unusedKeys, UnusedKey{
+				Source:     source.String(),
+				Key:        sourceKey,
+				Suggestion: closestKey(sourceKey, usedKeysSlice),
+			})
+		}
+	}
+
+	return unusedKeys
+}
+
+// closestKey returns the candidate key with the smallest edit
+// distance to the key given, as long as this distance is at most
+// a third of the key length. It returns the empty string otherwise.
+func closestKey(key string, candidates []string) (closest string) {
+	const lengthToDistanceRatio = 3
+	maxDistance := max(1, len(key)/lengthToDistanceRatio)
+	bestDistance := maxDistance + 1
+	for _, candidate := range candidates {
+		distance := editDistance(key, candidate)
+		if distance < bestDistance {
+			bestDistance = distance
+			closest = candidate
+		}
+	}
+	return closest
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) (distance int) {
+	aRunes, bRunes := []rune(a), []rune(b)
+	previousRow := make([]int, len(bRunes)+1)
+	currentRow := make([]int, len(bRunes)+1)
+	for j := range previousRow {
+		previousRow[j] = j
+	}
+
+	for i := 1; i <= len(aRunes); i++ {
+		currentRow[0] = i
+		for j := 1; j <= len(bRunes); j++ {
+			substitutionCost := 1
+			if aRunes[i-1] == bRunes[j-1] {
+				substitutionCost = 0
+			}
+			currentRow[j] = min(
+				previousRow[j]+1,                  // deletion
+				currentRow[j-1]+1,                 // insertion
+				previousRow[j-1]+substitutionCost, // substitution
+			)
+		}
+		previousRow, currentRow = currentRow, previousRow
+	}
+	return previousRow[len(bRunes)]
+}
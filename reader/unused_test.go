@@ -0,0 +1,122 @@
+package reader
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/qdm12/gosettings/reader/sources/env"
+	"github.com/qdm12/gosettings/reader/sources/flag"
+)
+
+func Test_Reader_UnusedKeys(t *testing.T) {
+	t.Parallel()
+
+	flagSource := flag.NewStrict([]string{"program",
+		"--server-adress=:8000", "--log-level=info", "--x"})
+	envSource := env.New(env.Settings{
+		Environ: []string{
+			"APP_SERVER_ADDRESS=:9000",
+			"APP_OLD_NAME=name",
+			"APP_PASWORD=secret",
+			"APP_TOKEN_FILE=/run/secrets/token",
+			"APP_CONFIG_FILE=/etc/app.yaml",
+			"HOME=/root",
+		},
+		KeyPrefix:  "APP_",
+		FileSuffix: "_FILE",
+	})
+	reader := New(Settings{
+		Sources: []Source{flagSource, envSource, &testSource{}},
+	})
+
+	_ = reader.String("SERVER_ADDRESS")
+	_ = reader.String("LOG_LEVEL")
+	_ = reader.String("NAME", RetroKeys("OLD_NAME"))
+	_ = reader.String("PASSWORD")
+	_ = reader.String("TOKEN")
+	_ = reader.String("CONFIG_FILE")
+
+	unusedKeys := reader.UnusedKeys()
+
+	expectedUnusedKeys := []UnusedKey{
+		{Source: "flag", Key: "server-adress", Suggestion: "server-address"},
+		{Source: "flag", Key: "x"},
+		{Source: "environment variable", Key: "APP_PASWORD", Suggestion: "APP_PASSWORD"},
+	}
+	if !reflect.DeepEqual(unusedKeys, expectedUnusedKeys) {
+		t.Errorf("expected %#v, got %#v", expectedUnusedKeys, unusedKeys)
+	}
+
+	err := reader.CheckUnusedKeys()
+	const expectedErrMessage = "unused keys found: " +
+		"flag server-adress (did you mean server-address?), flag x"
+	if !errors.Is(err, ErrUnusedKeys) || err.Error() != expectedErrMessage {
+		t.Errorf("expected error %q, got %v", expectedErrMessage, err)
+	}
+}
+
+func Test_Reader_UnusedKeys_envWithoutPrefix(t *testing.T) {
+	t.Parallel()
+
+	reader := New(Settings{
+		Sources: []Source{env.New(env.Settings{
+			Environ: []string{"SERVER_ADDRESS=:9000", "HOME=/root", "PATH=/bin"},
+		})},
+	})
+
+	_ = reader.String("SERVER_ADDRESS")
+
+	unusedKeys := reader.UnusedKeys()
+	if len(unusedKeys) != 0 {
+		t.Errorf("expected no unused key, got %#v", unusedKeys)
+	}
+}
+
+func Test_editDistance(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		a        string
+		b        string
+		distance int
+	}{
+		"both_empty": {},
+		"a_empty": {
+			b:        "abc",
+			distance: 3,
+		},
+		"equal": {
+			a: "abc",
+			b: "abc",
+		},
+		"missing_letter": {
+			a:        "SERVER_ADRESS",
+			b:        "SERVER_ADDRESS",
+			distance: 1,
+		},
+		"transposition": {
+			a:        "ab",
+			b:        "ba",
+			distance: 2,
+		},
+		"kitten": {
+			a:        "kitten",
+			b:        "sitting",
+			distance: 3,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			distance := editDistance(testCase.a, testCase.b)
+
+			if distance != testCase.distance {
+				t.Errorf("expected distance %d, got %d", testCase.distance, distance)
+			}
+		})
+	}
+}
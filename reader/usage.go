@@ -0,0 +1,165 @@
+package reader
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/qdm12/gosettings"
+)
+
+// KeyInfo contains metadata about a settings key, used to
+// render usage text with the Reader Usage method.
+type KeyInfo struct {
+	// Key is the key as queried on the reader, for example
+	// SERVER_ADDRESS. It must be set.
+	Key string
+	// Description is the description of the setting,
+	// which can span multiple lines.
+	Description string
+	// Type is the type of the setting value, for example
+	// `duration` or `bool`. It is optional.
+	Type string
+	// Default is the default value of the setting,
+	// for example `1s`. It is optional.
+	Default string
+	// AllowedValues are the values allowed for the setting,
+	// for example `debug`, `info`, `warn` and `error`.
+	// They are optional.
+	AllowedValues []string
+	// RetroKeys are retro-compatible keys for the key,
+	// as given to the RetroKeys option. They are optional.
+	RetroKeys []string
+	// Secret indicates the setting value is secret,
+	// in which case its default value is obfuscated.
+	Secret bool
+}
+
+type registeredKeys struct {
+	mutex    sync.Mutex
+	keyInfos []KeyInfo
+}
+
+func newRegisteredKeys() *registeredKeys {
+	return &registeredKeys{}
+}
+
+// Register registers metadata for the keys given, to be shown
+// by the Usage method. Registering an already registered key
+// replaces its metadata, keeping its original position.
+func (r *Reader) Register(keyInfos ...KeyInfo) {
+	r.registered.mutex.Lock()
+	defer r.registered.mutex.Unlock()
+	for _, keyInfo := range keyInfos {
+		replaced := false
+		for i, existing := range r.registered.keyInfos {
+			if existing.Key == keyInfo.Key {
+				r.registered.keyInfos[i] = keyInfo
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			r.registered.keyInfos = append(r.registered.keyInfos, keyInfo)
+		}
+	}
+}
+
+// KeyInfos returns a copy of the registered keys metadata,
+// in their registration order.
+func (r *Reader) KeyInfos() (keyInfos []KeyInfo) {
+	r.registered.mutex.Lock()
+	defer r.registered.mutex.Unlock()
+	keyInfos = make([]KeyInfo, len(r.registered.keyInfos))
+	for i, keyInfo := range r.registered.keyInfos {
+		keyInfo.AllowedValues = gosettings.CopySlice(keyInfo.AllowedValues)
+		keyInfo.RetroKeys = gosettings.CopySlice(keyInfo.RetroKeys)
+		keyInfos[i] = keyInfo
+	}
+	return keyInfos
+}
+
+// Usage returns usage text for all the registered keys, in their
+// registration order. Each key is shown in the form of each reader
+// source implementing the KeyFormatter interface, such as the flag
+// and environment variable sources, for example:
+//
+//	--server-address, SERVER_ADDRESS (string, default: :8000)
+//	    Listening address of the server.
+//	    Retro-compatible keys: --listening-address, LISTENING_ADDRESS
+//
+// If no source implements the KeyFormatter interface,
+// keys are shown as they are registered.
+func (r *Reader) Usage() string {
+	keyInfos := r.KeyInfos()
+	const indent = "    "
+	lines := make([]string, 0, len(keyInfos))
+	for _, keyInfo := range keyInfos {
+		line := strings.Join(r.formatKey(keyInfo.Key), ", ")
+		details := usageDetails(keyInfo)
+		if len(details) > 0 {
+			line += " (" + strings.Join(details, ", ") + ")"
+		}
+		lines = append(lines, line)
+
+		if keyInfo.Description != "" {
+			for _, descriptionLine := range strings.Split(keyInfo.Description, "\n") {
+				lines = append(lines, indent+descriptionLine)
+			}
+		}
+
+		if len(keyInfo.RetroKeys) > 0 {
+			retroForms := make([]string, 0, len(keyInfo.RetroKeys))
+			for _, retroKey := range keyInfo.RetroKeys {
+				retroForms = append(retroForms, r.formatKey(retroKey)...)
+			}
+			lines = append(lines, indent+"Retro-compatible keys: "+strings.Join(retroForms, ", "))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatKey returns the deduplicated forms of the key for
+// each source implementing the KeyFormatter interface.
+func (r *Reader) formatKey(key string) (forms []string) {
+	for _, source := range r.sources {
+		formatter, ok := source.(KeyFormatter)
+		if !ok {
+			continue
+		}
+		form := formatter.FormatKey(key)
+		duplicate := false
+		for _, existing := range forms {
+			if existing == form {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			forms = append(forms, form)
+		}
+	}
+	if len(forms) == 0 {
+		return []string{key}
+	}
+	return forms
+}
+
+func usageDetails(keyInfo KeyInfo) (details []string) {
+	if keyInfo.Type != "" {
+		details = append(details, keyInfo.Type)
+	}
+	if keyInfo.Default != "" {
+		defaultValue := keyInfo.Default
+		if keyInfo.Secret {
+			defaultValue = gosettings.ObfuscateKey(defaultValue)
+		}
+		details = append(details, "default: "+defaultValue)
+	}
+	if len(keyInfo.AllowedValues) > 0 {
+		details = append(details, "one of "+strings.Join(keyInfo.AllowedValues, ", "))
+	}
+	if keyInfo.Secret {
+		details = append(details, "secret")
+	}
+	return details
+}
@@ -0,0 +1,99 @@
+package reader
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/qdm12/gosettings/reader/sources/env"
+	"github.com/qdm12/gosettings/reader/sources/flag"
+)
+
+func Test_Reader_Usage(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		sources  []Source
+		keyInfos []KeyInfo
+		usage    string
+	}{
+		"no_key_registered": {
+			sources: []Source{flag.New(nil)},
+		},
+		"flag_and_env": {
+			sources: []Source{
+				flag.New(nil),
+				env.New(env.Settings{KeyPrefix: "APP_"}),
+			},
+			keyInfos: []KeyInfo{
+				{
+					Key:         "SERVER_ADDRESS",
+					Description: "Listening address of the server.\nIt can be an IP address and a port.",
+					Type:        "string",
+					Default:     ":8000",
+					RetroKeys:   []string{"LISTENING_ADDRESS"},
+				},
+				{
+					Key:     "API_KEY",
+					Type:    "string",
+					Default: "abc",
+					Secret:  true,
+				},
+				{
+					Key:           "LOG_LEVEL",
+					AllowedValues: []string{"info", "debug"},
+				},
+				{
+					Key: "ENABLED",
+				},
+			},
+			usage: `--server-address, APP_SERVER_ADDRESS (string, default: :8000)
+    Listening address of the server.
+    It can be an IP address and a port.
+    Retro-compatible keys: --listening-address, APP_LISTENING_ADDRESS
+--api-key, APP_API_KEY (string, default: [set], secret)
+--log-level, APP_LOG_LEVEL (one of info, debug)
+--enabled, APP_ENABLED`,
+		},
+		"no_key_formatter_source": {
+			sources: []Source{&testSource{}},
+			keyInfos: []KeyInfo{
+				{Key: "KEY", Description: "Some key."},
+			},
+			usage: "KEY\n    Some key.",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			reader := New(Settings{Sources: testCase.sources})
+			reader.Register(testCase.keyInfos...)
+
+			usage := reader.Usage()
+
+			if usage != testCase.usage {
+				t.Errorf("expected usage:\n%s\ngot:\n%s", testCase.usage, usage)
+			}
+		})
+	}
+}
+
+func Test_Reader_Register(t *testing.T) {
+	t.Parallel()
+
+	reader := New(Settings{Sources: []Source{&testSource{}}})
+
+	reader.Register(KeyInfo{Key: "A"}, KeyInfo{Key: "B"})
+	reader.Register(KeyInfo{Key: "A", Description: "replaced"})
+
+	expected := []KeyInfo{
+		{Key: "A", Description: "replaced"},
+		{Key: "B"},
+	}
+	keyInfos := reader.KeyInfos()
+	if !reflect.DeepEqual(keyInfos, expected) {
+		t.Errorf("expected %v, got %v", expected, keyInfos)
+	}
+}
@@ -0,0 +1,138 @@
+package reader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/qdm12/gosettings"
+	"github.com/qdm12/gosettings/internal/parse"
+)
+
+// WatchSettings contains settings for the Reader Watch method.
+type WatchSettings struct {
+	// PollInterval is the interval at which sources implementing
+	// the Reloader interface are re-read, in addition to file
+	// system notifications on Linux. It defaults to 5 seconds.
+	PollInterval time.Duration
+	// Debounce is the duration to wait for no further file system
+	// notification before re-reading sources, for editors writing
+	// files in several steps. It defaults to 100 milliseconds.
+	Debounce time.Duration
+	// HandleError is called when encountering an error setting up
+	// file system notifications or reloading a source, in which case
+	// the source keeps its previous values. It defaults to a no-op
+	// function.
+	HandleError func(err error)
+}
+
+func (w *WatchSettings) setDefaults() {
+	const defaultPollInterval = 5 * time.Second
+	w.PollInterval = gosettings.DefaultComparable(w.PollInterval, defaultPollInterval)
+	const defaultDebounce = 100 * time.Millisecond
+	w.Debounce = gosettings.DefaultComparable(w.Debounce, defaultDebounce)
+	if w.HandleError == nil { // Note: cannot use DefaultInterface
+		w.HandleError = func(err error) {}
+	}
+}
+
+// Change is a key change detected by the Reader Watch method.
+type Change struct {
+	// Source is the name of the source containing the key,
+	// for example 'TOML file config.toml'.
+	Source string
+	// Key is the key in the form given by the source KeyTransform
+	// method, for example SERVER_ADDRESS.
+	Key string
+	// QueriedKey is the key as it was queried on the reader matching
+	// the changed key, for example SERVER_ADDRESS, or the empty
+	// string if no queried key matches the changed key.
+	QueriedKey string
+}
+
+var ErrNoReloadableSource = errors.New("no source implements the Reloader interface")
+
+// Watch re-reads sources implementing the Reloader interface when
+// their files change, and calls the callback with the keys changed,
+// only if at least one key changed. Changes are detected by polling
+// at the interval given in the reader watch settings, and on Linux
+// using file system notifications, debounced with the debounce
+// duration given in the reader watch settings.
+// Watch blocks until the context is canceled, and returns an error
+// only if no source implements the Reloader interface.
+// The callback is called sequentially from the Watch goroutine,
+// and can call any reader method to read updated values.
+func (r *Reader) Watch(ctx context.Context, callback func(changes []Change)) (err error) {
+	reloadables := make([]parse.Source, 0, len(r.sources))
+	var paths []string
+	for _, source := range r.sources {
+		reloader, ok := source.(Reloader)
+		if !ok {
+			continue
+		}
+		reloadables = append(reloadables, source)
+		paths = append(paths, reloader.WatchPaths()...)
+	}
+
+	if len(reloadables) == 0 {
+		return ErrNoReloadableSource
+	}
+
+	notifications, stopNotifier, err := newNotifier(paths)
+	if err != nil {
+		r.watchSettings.HandleError(fmt.Errorf("setting up file system notifications: %w", err))
+	} else {
+		defer stopNotifier()
+	}
+
+	ticker := time.NewTicker(r.watchSettings.PollInterval)
+	defer ticker.Stop()
+	debounceTimer := time.NewTimer(time.Hour)
+	debounceTimer.Stop()
+	defer debounceTimer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-notifications:
+			debounceTimer.Reset(r.watchSettings.Debounce)
+			continue
+		case <-debounceTimer.C:
+		case <-ticker.C:
+		}
+
+		changes := r.reload(reloadables)
+		if len(changes) > 0 {
+			callback(changes)
+		}
+	}
+}
+
+func (r *Reader) reload(reloadables []parse.Source) (changes []Change) {
+	queriedKeys := r.queried.list()
+	for _, source := range reloadables {
+		reloader := source.(Reloader) //nolint:forcetypeassert
+		changedKeys, err := reloader.Reload()
+		if err != nil {
+			r.watchSettings.HandleError(fmt.Errorf("reloading %s: %w", source, err))
+			continue
+		}
+
+		for _, changedKey := range changedKeys {
+			change := Change{
+				Source: source.String(),
+				Key:    changedKey,
+			}
+			for _, queriedKey := range queriedKeys {
+				if source.KeyTransform(queriedKey) == changedKey {
+					change.QueriedKey = queriedKey
+					break
+				}
+			}
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
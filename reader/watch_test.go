@@ -0,0 +1,127 @@
+package reader
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testReloaderSource struct {
+	testSource
+	reloadErr error
+	// nextKeyValue, if not nil, replaces the key values of the
+	// source on the next reload, which returns changedKeys.
+	nextKeyValue map[string]string
+	changedKeys  []string
+}
+
+func (t *testReloaderSource) Reload() (changedKeys []string, err error) {
+	if t.reloadErr != nil {
+		return nil, t.reloadErr
+	} else if t.nextKeyValue == nil {
+		return nil, nil
+	}
+	t.keyValue, t.nextKeyValue = t.nextKeyValue, nil
+	return t.changedKeys, nil
+}
+
+func (t *testReloaderSource) WatchPaths() (paths []string) { return nil }
+
+func Test_Reader_Watch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no_reloadable_source", func(t *testing.T) {
+		t.Parallel()
+
+		reader := New(Settings{Sources: []Source{&testSource{}}})
+
+		err := reader.Watch(context.Background(), func(changes []Change) {})
+
+		if !errors.Is(err, ErrNoReloadableSource) {
+			t.Errorf("expected error %v to wrap %v", err, ErrNoReloadableSource)
+		}
+	})
+
+	t.Run("reload_error", func(t *testing.T) {
+		t.Parallel()
+
+		errTest := errors.New("test error")
+		errCh := make(chan error, 1)
+		reader := New(Settings{
+			Sources: []Source{&testReloaderSource{reloadErr: errTest}},
+			Watch: WatchSettings{
+				PollInterval: time.Millisecond,
+				HandleError: func(err error) {
+					select {
+					case errCh <- err:
+					default:
+					}
+				},
+			},
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			_ = reader.Watch(ctx, func(changes []Change) {
+				t.Error("callback must not be called")
+			})
+		}()
+
+		err := <-errCh
+		if !errors.Is(err, errTest) {
+			t.Errorf("expected error %v to wrap %v", err, errTest)
+		}
+		const expectedMessage = "reloading test: test error"
+		if err.Error() != expectedMessage {
+			t.Errorf("expected error message %q but got %q", expectedMessage, err.Error())
+		}
+	})
+	t.Run("value_changed", func(t *testing.T) {
+		t.Parallel()
+
+		source := &testReloaderSource{
+			testSource: testSource{
+				keyValue: map[string]string{"KEY": "old", "OTHER": "other"},
+			},
+			nextKeyValue: map[string]string{"KEY": "new", "OTHER": "other"},
+			changedKeys:  []string{"KEY"},
+		}
+		reader := New(Settings{
+			Sources: []Source{source},
+			Watch:   WatchSettings{PollInterval: time.Millisecond},
+		})
+
+		oldValue := reader.String("KEY")
+		if oldValue != "old" {
+			t.Fatalf("expected old value %q but got %q", "old", oldValue)
+		}
+
+		type callbackCall struct {
+			changes  []Change
+			newValue string
+		}
+		calls := make(chan callbackCall, 1)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			_ = reader.Watch(ctx, func(changes []Change) {
+				calls <- callbackCall{
+					changes:  changes,
+					newValue: reader.String("KEY"),
+				}
+			})
+		}()
+
+		call := <-calls
+		expectedChanges := []Change{{Source: "test", Key: "KEY", QueriedKey: "KEY"}}
+		if !reflect.DeepEqual(call.changes, expectedChanges) {
+			t.Errorf("expected changes %#v but got %#v", expectedChanges, call.changes)
+		}
+		if call.newValue != "new" {
+			t.Errorf("expected new value %q but got %q", "new", call.newValue)
+		}
+	})
+}
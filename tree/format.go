@@ -0,0 +1,155 @@
+package tree
+
+import (
+	"fmt"
+	"net/netip"
+	"time"
+
+	"github.com/qdm12/gosettings"
+)
+
+const notSet = "[not set]"
+
+// format formats the value given, without using reflection,
+// returning `[not set]` for nil pointers, empty strings and
+// invalid netip values.
+func format(value any) string { //nolint:cyclop
+	switch typed := value.(type) {
+	case nil:
+		return notSet
+	case bool:
+		return gosettings.BoolToYesNo(&typed)
+	case *bool:
+		return formatPointer(typed, func(b bool) string { return gosettings.BoolToYesNo(&b) })
+	case string:
+		return formatString(typed)
+	case *string:
+		return formatPointer(typed, formatString)
+	case time.Duration:
+		return typed.String()
+	case *time.Duration:
+		return formatPointer(typed, time.Duration.String)
+	case netip.Addr:
+		return formatValidator(typed)
+	case *netip.Addr:
+		return formatPointer(typed, formatValidator[netip.Addr])
+	case netip.AddrPort:
+		return formatValidator(typed)
+	case *netip.AddrPort:
+		return formatPointer(typed, formatValidator[netip.AddrPort])
+	case netip.Prefix:
+		return formatValidator(typed)
+	case *netip.Prefix:
+		return formatPointer(typed, formatValidator[netip.Prefix])
+	case *int:
+		return formatPointer(typed, sprint[int])
+	case *int8:
+		return formatPointer(typed, sprint[int8])
+	case *int16:
+		return formatPointer(typed, sprint[int16])
+	case *int32:
+		return formatPointer(typed, sprint[int32])
+	case *int64:
+		return formatPointer(typed, sprint[int64])
+	case *uint:
+		return formatPointer(typed, sprint[uint])
+	case *uint8:
+		return formatPointer(typed, sprint[uint8])
+	case *uint16:
+		return formatPointer(typed, sprint[uint16])
+	case *uint32:
+		return formatPointer(typed, sprint[uint32])
+	case *uint64:
+		return formatPointer(typed, sprint[uint64])
+	case *float32:
+		return formatPointer(typed, sprint[float32])
+	case *float64:
+		return formatPointer(typed, sprint[float64])
+	case fmt.Stringer:
+		return typed.String()
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func formatPointer[T any](pointer *T, formatValue func(value T) string) string {
+	if pointer == nil {
+		return notSet
+	}
+	return formatValue(*pointer)
+}
+
+func formatString(s string) string {
+	if s == "" {
+		return notSet
+	}
+	return s
+}
+
+type stringValidator interface {
+	String() string
+	IsValid() bool
+}
+
+func formatValidator[T stringValidator](value T) string {
+	if !value.IsValid() {
+		return notSet
+	}
+	return value.String()
+}
+
+func sprint[T any](value T) string {
+	return fmt.Sprint(value)
+}
+
+// obfuscate obfuscates the formatted value given,
+// keeping `[not set]` as is.
+func obfuscate(formatted string) string {
+	if formatted == notSet {
+		return notSet
+	}
+	return gosettings.ObfuscateKey(formatted)
+}
+
+// appendKnownSlice appends a list node for the value given
+// if it is a slice of a known type, and returns false otherwise.
+func (n *Node) appendKnownSlice(key string, value any) (child *Node, ok bool) { //nolint:cyclop
+	switch typed := value.(type) {
+	case []string:
+		return AppendList(n, key, typed), true
+	case []int:
+		return AppendList(n, key, typed), true
+	case []int8:
+		return AppendList(n, key, typed), true
+	case []int16:
+		return AppendList(n, key, typed), true
+	case []int32:
+		return AppendList(n, key, typed), true
+	case []int64:
+		return AppendList(n, key, typed), true
+	case []uint:
+		return AppendList(n, key, typed), true
+	case []uint8:
+		return AppendList(n, key, typed), true
+	case []uint16:
+		return AppendList(n, key, typed), true
+	case []uint32:
+		return AppendList(n, key, typed), true
+	case []uint64:
+		return AppendList(n, key, typed), true
+	case []float32:
+		return AppendList(n, key, typed), true
+	case []float64:
+		return AppendList(n, key, typed), true
+	case []time.Duration:
+		return AppendList(n, key, typed), true
+	case []netip.Addr:
+		return AppendList(n, key, typed), true
+	case []netip.AddrPort:
+		return AppendList(n, key, typed), true
+	case []netip.Prefix:
+		return AppendList(n, key, typed), true
+	default:
+		return nil, false
+	}
+}
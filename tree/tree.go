@@ -0,0 +1,116 @@
+// Package tree builds human readable indented trees of settings,
+// typically returned by a ToLinesNode method of a settings struct,
+// and rendered as a string by its String method.
+package tree
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Node is a node of a settings tree, with a text line
+// and optional child nodes.
+type Node struct {
+	text     string
+	children []*Node
+}
+
+// New creates a new root node with the text formatted
+// with the format and arguments given, as with fmt.Sprintf.
+// For example `tree.New("Server settings:")`.
+func New(format string, args ...any) (node *Node) {
+	return &Node{
+		text: fmt.Sprintf(format, args...),
+	}
+}
+
+// Append appends a child node with the text formatted with the
+// format and arguments given, and returns the child node so that
+// children can be added to it.
+func (n *Node) Append(format string, args ...any) (child *Node) {
+	child = New(format, args...)
+	n.children = append(n.children, child)
+	return child
+}
+
+// AppendNode appends the child node given, which is typically the
+// node of nested settings returned by their ToLinesNode method.
+// A nil child node is ignored.
+func (n *Node) AppendNode(child *Node) {
+	if child == nil {
+		return
+	}
+	n.children = append(n.children, child)
+}
+
+// AppendField appends a child node `key: value` where the value is
+// formatted automatically, with unset values shown as `[not set]`:
+//   - *bool and bool as `yes` or `no`
+//   - time.Duration using its String method
+//   - netip.Addr, netip.AddrPort and netip.Prefix using their String
+//     method, or `[not set]` if they are not valid
+//   - pointers to strings, numbers, durations and netip types as
+//     the value pointed to, or `[not set]` if they are nil
+//   - slices of strings, numbers, durations and netip types as a child
+//     list node, see AppendList
+//   - values implementing fmt.Stringer using their String method
+//   - other values using fmt.Sprint
+//
+// It returns the child node appended.
+func (n *Node) AppendField(key string, value any) (child *Node) {
+	if child, ok := n.appendKnownSlice(key, value); ok {
+		return child
+	}
+	return n.Append("%s: %s", key, format(value))
+}
+
+// AppendSecret appends a child node `key: obfuscated value`, where
+// the value is formatted as with AppendField and then obfuscated with
+// gosettings.ObfuscateKey, such that it can be shown in logs.
+// It returns the child node appended.
+func (n *Node) AppendSecret(key string, value any) (child *Node) {
+	return n.Append("%s: %s", key, obfuscate(format(value)))
+}
+
+// AppendList appends a child node `key:` to the node given, with
+// each of the values formatted as with AppendField as a list item
+// child node. If the values slice is empty, the node `key: [not set]`
+// is appended instead. It returns the child node appended.
+// Note this is a function and not a method, since methods
+// cannot have type parameters.
+func AppendList[T any](node *Node, key string, values []T) (child *Node) {
+	if len(values) == 0 {
+		return node.Append("%s: %s", key, notSet)
+	}
+	child = node.Append("%s:", key)
+	for _, value := range values {
+		child.Append("%s", format(value))
+	}
+	return child
+}
+
+// String returns the tree as indented lines, for example:
+//
+//	Settings:
+//	├── Enabled: yes
+//	├── Names:
+//	│   ├── Alice
+//	│   └── Bob
+//	└── Server:
+//	    └── Address: :8000
+func (n *Node) String() string {
+	builder := new(strings.Builder)
+	n.write(builder, "", "")
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
+func (n *Node) write(builder *strings.Builder, linePrefix, childrenPrefix string) {
+	builder.WriteString(linePrefix + n.text + "\n")
+	for i, child := range n.children {
+		if i == len(n.children)-1 {
+			child.write(builder, childrenPrefix+"└── ", childrenPrefix+"    ")
+		} else {
+			child.write(builder, childrenPrefix+"├── ", childrenPrefix+"│   ")
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package tree
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func ptrTo[T any](x T) *T { return &x }
+
+func Test_Node_String(t *testing.T) {
+	t.Parallel()
+
+	root := New("Settings:")
+	root.AppendField("Enabled", ptrTo(true))
+	AppendList(root, "Names", []string{"Alice", "Bob"})
+	root.AppendSecret("Password", "password")
+	server := New("Server:")
+	server.AppendField("Address", netip.MustParseAddrPort("1.2.3.4:8000"))
+	server.AppendField("Timeout", 10*time.Second)
+	root.AppendNode(server)
+	root.AppendNode(nil)
+
+	const expected = `Settings:
+├── Enabled: yes
+├── Names:
+│   ├── Alice
+│   └── Bob
+├── Password: [set]
+└── Server:
+    ├── Address: 1.2.3.4:8000
+    └── Timeout: 10s`
+
+	if s := root.String(); s != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, s)
+	}
+}
+
+func Test_Node_AppendField(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		value any
+		text  string
+	}{
+		"nil":              {text: "key: [not set]"},
+		"bool":             {value: false, text: "key: no"},
+		"nil_bool_pointer": {value: (*bool)(nil), text: "key: [not set]"},
+		"bool_pointer":     {value: ptrTo(true), text: "key: yes"},
+		"empty_string":     {value: "", text: "key: [not set]"},
+		"string_pointer":   {value: ptrTo("x"), text: "key: x"},
+		"duration":         {value: time.Minute, text: "key: 1m0s"},
+		"duration_pointer": {value: ptrTo(time.Second), text: "key: 1s"},
+		"invalid_addr":     {value: netip.Addr{}, text: "key: [not set]"},
+		"addr_pointer":     {value: ptrTo(netip.MustParseAddr("::1")), text: "key: ::1"},
+		"prefix":           {value: netip.MustParsePrefix("10.0.0.0/8"), text: "key: 10.0.0.0/8"},
+		"nil_int":          {value: (*int)(nil), text: "key: [not set]"},
+		"int_pointer":      {value: ptrTo(-1), text: "key: -1"},
+		"int8_pointer":     {value: ptrTo(int8(-8)), text: "key: -8"},
+		"int16_pointer":    {value: ptrTo(int16(-16)), text: "key: -16"},
+		"int32_pointer":    {value: ptrTo(int32(-32)), text: "key: -32"},
+		"int64_pointer":    {value: ptrTo(int64(-64)), text: "key: -64"},
+		"nil_int8":         {value: (*int8)(nil), text: "key: [not set]"},
+		"uint_pointer":     {value: ptrTo(uint(1)), text: "key: 1"},
+		"uint8_pointer":    {value: ptrTo(uint8(8)), text: "key: 8"},
+		"nil_uint16":       {value: (*uint16)(nil), text: "key: [not set]"},
+		"uint16_pointer":   {value: ptrTo(uint16(8000)), text: "key: 8000"},
+		"uint32_pointer":   {value: ptrTo(uint32(32)), text: "key: 32"},
+		"uint64_pointer":   {value: ptrTo(uint64(64)), text: "key: 64"},
+		"float32_pointer":  {value: ptrTo(float32(1.5)), text: "key: 1.5"},
+		"nil_float32":      {value: (*float32)(nil), text: "key: [not set]"},
+		"float64_pointer":  {value: ptrTo(2.5), text: "key: 2.5"},
+		"int":              {value: 5, text: "key: 5"},
+		"empty_slice":      {value: []string{}, text: "key: [not set]"},
+		"string_slice":     {value: []string{"a", "b"}, text: "key:\n├── a\n└── b"},
+		"int_slice":        {value: []int{1, 2}, text: "key:\n├── 1\n└── 2"},
+		"int8_slice":       {value: []int8{1, 2}, text: "key:\n├── 1\n└── 2"},
+		"int16_slice":      {value: []int16{1, 2}, text: "key:\n├── 1\n└── 2"},
+		"int32_slice":      {value: []int32{1, 2}, text: "key:\n├── 1\n└── 2"},
+		"int64_slice":      {value: []int64{1, 2}, text: "key:\n├── 1\n└── 2"},
+		"uint_slice":       {value: []uint{1, 2}, text: "key:\n├── 1\n└── 2"},
+		"uint8_slice":      {value: []uint8{1, 2}, text: "key:\n├── 1\n└── 2"},
+		"uint16_slice":     {value: []uint16{1, 2}, text: "key:\n├── 1\n└── 2"},
+		"uint32_slice":     {value: []uint32{1, 2}, text: "key:\n├── 1\n└── 2"},
+		"uint64_slice":     {value: []uint64{1, 2}, text: "key:\n├── 1\n└── 2"},
+		"float32_slice":    {value: []float32{1.5, 2}, text: "key:\n├── 1.5\n└── 2"},
+		"float64_slice":    {value: []float64{1.5, 2}, text: "key:\n├── 1.5\n└── 2"},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			node := New("root")
+
+			child := node.AppendField("key", testCase.value)
+
+			if s := child.String(); s != testCase.text {
+				t.Errorf("expected text %q, got %q", testCase.text, s)
+			}
+		})
+	}
+}
+
+func Test_Node_AppendSecret(t *testing.T) {
+	t.Parallel()
+
+	node := New("root")
+
+	unset := node.AppendSecret("key", (*string)(nil))
+	set := node.AppendSecret("key", ptrTo("password"))
+
+	if unset.text != "key: [not set]" {
+		t.Errorf("expected unset secret text, got %q", unset.text)
+	}
+	if set.text != "key: [set]" {
+		t.Errorf("expected set secret text, got %q", set.text)
+	}
+}